@@ -19,11 +19,33 @@ func main() {
 	dbfilename := flag.String("dbfilename", "dump.rdb", "RDB file name")
 	port := flag.String("port", "6379", "port to run the server on")
 	replicaof := flag.String("replicaof", "", "Master host and port (for replica mode)")
+	protoMaxBulkLen := flag.String("proto-max-bulk-len", "512mb", "largest bulk argument a client may send")
+	requirepass := flag.String("requirepass", "", "password clients must AUTH with")
+	hashMaxListpackEntries := flag.String("hash-max-listpack-entries", "128", "most fields a hash may have in the compact encoding")
+	hashMaxListpackValue := flag.String("hash-max-listpack-value", "64", "longest field or value a hash may have in the compact encoding")
+	setMaxIntsetEntries := flag.String("set-max-intset-entries", "512", "most members a set of integers may have in the compact encoding")
+	hllSparseMaxBytes := flag.String("hll-sparse-max-bytes", "3000", "largest size of a HyperLogLog in the sparse encoding")
 
 	flag.Parse()
 
 	commands.SetConfig("dir", *dir)
 	commands.SetConfig("dbfilename", *dbfilename)
+	if err := commands.ApplyConfig("proto-max-bulk-len", *protoMaxBulkLen); err != nil {
+		log.Fatal("Invalid configuration: ", err)
+	}
+	commands.SetConfig("requirepass", *requirepass)
+	if err := commands.ApplyConfig("hash-max-listpack-entries", *hashMaxListpackEntries); err != nil {
+		log.Fatal("Invalid configuration: ", err)
+	}
+	if err := commands.ApplyConfig("hash-max-listpack-value", *hashMaxListpackValue); err != nil {
+		log.Fatal("Invalid configuration: ", err)
+	}
+	if err := commands.ApplyConfig("set-max-intset-entries", *setMaxIntsetEntries); err != nil {
+		log.Fatal("Invalid configuration: ", err)
+	}
+	if err := commands.ApplyConfig("hll-sparse-max-bytes", *hllSparseMaxBytes); err != nil {
+		log.Fatal("Invalid configuration: ", err)
+	}
 
 	if *replicaof != "" {
 		commands.SetConfig("role", "slave")
@@ -50,6 +72,8 @@ func main() {
 		commands.SetKeyEntry(key, value)
 	}
 
+	commands.StartActiveExpireCycle()
+
 	fmt.Println("Starting server on port", *port, "...")
 	err = server.Start("0.0.0.0:" + *port)
 	if err != nil {
@@ -0,0 +1,186 @@
+package commands
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+)
+
+// Keys with a TTL are removed in two ways: lazily, when a command touches an
+// expired key, and actively, by a background cycle that samples the expires
+// index so keys nobody reads again don't stay in memory forever. Both paths
+// go through expireKey, which sends a DEL to the replicas. Replicas never
+// expire keys themselves; they report them as missing and wait for that DEL.
+//
+// Hash fields with a TTL work the same way, with HDEL: they are reclaimed
+// by lookupHashWrite and by the cycle, which also samples the index of
+// hashes with field TTLs.
+const (
+	serverHz = 10 // active expire cycles per second
+
+	activeExpireKeysPerLoop     = 20 // keys sampled per batch
+	activeExpireAcceptableStale = 10 // % of expired keys in a sample worth another batch
+	activeExpireCycleTimePerc   = 25 // % of each tick a cycle may use
+)
+
+var expireStats struct {
+	expiredKeys          atomic.Int64
+	expiredSubkeys       atomic.Int64 // hash fields
+	timeCapReachedCount  atomic.Int64
+	cycleCPUMicroseconds atomic.Int64
+	stalePerc            atomic.Uint64 // float64 bits
+}
+
+func isExpired(entry core.StoreEntry, now int64) bool {
+	return entry.ExpiresAt > 0 && now > entry.ExpiresAt
+}
+
+// expireIfNeeded reports whether key has expired, deleting it unless we are
+// a replica. Callers must hold the write lock on mu.
+func expireIfNeeded(key string) bool {
+	entry, exists := store.Get(key)
+	if !exists || !isExpired(entry, time.Now().UnixMilli()) {
+		return false
+	}
+	if configs["role"] != "slave" {
+		expireKey(key)
+	}
+	return true
+}
+
+func expireKey(key string) {
+	deleteKey(key)
+	expireStats.expiredKeys.Add(1)
+	propagateCommand([]string{"DEL", key})
+}
+
+// expireHashFields deletes the fields of the hash at key whose TTL passed,
+// and the key if that leaves it empty, which it reports. On a replica the
+// fields are left for the master's HDEL. Callers must hold mu.
+func expireHashFields(key string, hash *core.Hash) bool {
+	if configs["role"] == "slave" {
+		return false
+	}
+	fields := hash.DeleteExpired(time.Now().UnixMilli())
+	if len(fields) == 0 {
+		return false
+	}
+
+	expireStats.expiredSubkeys.Add(int64(len(fields)))
+	propagateCommand(append([]string{"HDEL", key}, fields...))
+	signalModifiedKey(key)
+	updateHashFieldExpires(key, hash)
+	if hash.Len() == 0 {
+		deleteKey(key)
+		return true
+	}
+	return false
+}
+
+// StartActiveExpireCycle runs activeExpireCycle serverHz times a second.
+func StartActiveExpireCycle() {
+	go func() {
+		ticker := time.NewTicker(time.Second / serverHz)
+		defer ticker.Stop()
+		for range ticker.C {
+			if role, _ := GetConfig("role"); role == "slave" {
+				continue
+			}
+			activeExpireCycle()
+		}
+	}()
+}
+
+// activeExpireCycle deletes expired keys in batches of random samples from
+// the expires index. As long as enough of a sample had expired there are
+// probably many more, so it keeps going until the stale fraction drops
+// below activeExpireAcceptableStale or the cycle's time budget runs out.
+// The lock is released between batches so clients aren't held up.
+func activeExpireCycle() {
+	start := time.Now()
+	timeLimit := time.Second / serverHz * activeExpireCycleTimePerc / 100
+
+	totalSampled, totalExpired := activeExpireBatches(activeExpireSample, start, timeLimit)
+	activeExpireBatches(activeExpireHashSample, start, timeLimit)
+
+	expireStats.cycleCPUMicroseconds.Add(time.Since(start).Microseconds())
+
+	// a running average, weighted like Redis's expired_stale_perc
+	current := 0.0
+	if totalSampled > 0 {
+		current = float64(totalExpired) / float64(totalSampled)
+	}
+	stale := math.Float64frombits(expireStats.stalePerc.Load())
+	expireStats.stalePerc.Store(math.Float64bits(current*0.05 + stale*0.95))
+}
+
+// activeExpireBatches runs sample until a batch finds few enough expired
+// entries or the cycle that started at start has used up timeLimit.
+func activeExpireBatches(sample func() (int, int), start time.Time, timeLimit time.Duration) (totalSampled, totalExpired int) {
+	for {
+		execMu.RLock()
+		mu.Lock()
+		sampled, expired := sample()
+		mu.Unlock()
+		execMu.RUnlock()
+
+		totalSampled += sampled
+		totalExpired += expired
+		if sampled == 0 || expired*100/sampled <= activeExpireAcceptableStale {
+			return totalSampled, totalExpired
+		}
+		if time.Since(start) > timeLimit {
+			expireStats.timeCapReachedCount.Add(1)
+			return totalSampled, totalExpired
+		}
+	}
+}
+
+// activeExpireSample checks up to activeExpireKeysPerLoop keys with a TTL
+// and expires the ones past it. Map iteration starts at a random position,
+// which is all the randomness sampling needs. Callers must hold mu.
+func activeExpireSample() (sampled, expired int) {
+	now := time.Now().UnixMilli()
+
+	var batch []string
+	for key, at := range expires {
+		if sampled == activeExpireKeysPerLoop {
+			break
+		}
+		sampled++
+		if now > at {
+			batch = append(batch, key)
+		}
+	}
+
+	for _, key := range batch {
+		expireKey(key)
+	}
+	return sampled, len(batch)
+}
+
+// activeExpireHashSample is activeExpireSample for hash fields: it checks up
+// to activeExpireKeysPerLoop hashes with field TTLs and reclaims the expired
+// fields of those that have some. Callers must hold mu.
+func activeExpireHashSample() (sampled, expired int) {
+	now := time.Now().UnixMilli()
+
+	var batch []string
+	for key, at := range hashFieldExpires {
+		if sampled == activeExpireKeysPerLoop {
+			break
+		}
+		sampled++
+		if now > at {
+			batch = append(batch, key)
+		}
+	}
+
+	for _, key := range batch {
+		entry, _ := store.Get(key)
+		expireHashFields(key, entry.Data.(*core.Hash))
+	}
+	return sampled, len(batch)
+}
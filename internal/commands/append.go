@@ -0,0 +1,25 @@
+package commands
+
+// HandleAppend appends value to the string at key, creating it if needed,
+// and replies with the new length: APPEND key value.
+func HandleAppend(c *Client, args []string) error {
+	key, value := args[1], args[2]
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	current, exists, err := lookupStringWrite(key)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if err := checkStringLength(int64(len(current) + len(value))); err != nil {
+			return err
+		}
+		value = current + value
+	}
+	setStringKeepTTL(key, value)
+
+	c.Reply.Integer(int64(len(value)))
+	return nil
+}
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	models "github.com/codecrafters-io/redis-starter-go/internal/models/core"
+)
+
+var errWrongPass = errors.New("WRONGPASS invalid username-password pair or user is disabled.")
+
+// authRequired reports whether c still has to authenticate before it may
+// run commands. Only the "default" user exists: it needs no password unless
+// requirepass is set.
+func authRequired(c *Client) bool {
+	if password, _ := GetConfig("requirepass"); password == "" {
+		return false
+	}
+
+	models.ClientMu.Lock()
+	defer models.ClientMu.Unlock()
+	return !c.Authenticated
+}
+
+func HandleAuth(c *Client, args []string) error {
+	username, password := "default", args[1]
+	if len(args) == 3 {
+		username, password = args[1], args[2]
+	} else if len(args) > 3 {
+		return errSyntax
+	}
+
+	if required, _ := GetConfig("requirepass"); required == "" && len(args) == 2 {
+		return errors.New("ERR AUTH <password> called without any password configured for the default user. Are you sure your configuration is correct?")
+	}
+	if err := authenticate(c, username, password); err != nil {
+		return err
+	}
+
+	c.Reply.OK()
+	return nil
+}
+
+// authenticate checks the credentials and marks the connection as
+// authenticated.
+func authenticate(c *Client, username, password string) error {
+	required, _ := GetConfig("requirepass")
+	if username != "default" {
+		return errWrongPass
+	}
+	if required != "" && subtle.ConstantTimeCompare([]byte(password), []byte(required)) != 1 {
+		return errWrongPass
+	}
+
+	models.ClientMu.Lock()
+	c.Authenticated = true
+	models.ClientMu.Unlock()
+	return nil
+}
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// parseBitRange parses the [start end [BYTE|BIT]] arguments of BITCOUNT
+// and BITPOS, from args[i] on, and turns them into a range of bits of a
+// string of length n, both ends included. Negative offsets count from the
+// end. It reports false if the range is empty.
+func parseBitRange(args []string, i int, n int64) (startBit, endBit int64, ok bool, err error) {
+	start, err := strconv.ParseInt(args[i], 10, 64)
+	if err != nil {
+		return 0, 0, false, errNotInteger
+	}
+	end := int64(-1)
+	isBit := false
+	if i+1 < len(args) {
+		if end, err = strconv.ParseInt(args[i+1], 10, 64); err != nil {
+			return 0, 0, false, errNotInteger
+		}
+	}
+	if i+2 < len(args) {
+		switch strings.ToUpper(args[i+2]) {
+		case "BIT":
+			isBit = true
+		case "BYTE":
+		default:
+			return 0, 0, false, errSyntax
+		}
+	}
+	if i+3 < len(args) {
+		return 0, 0, false, errSyntax
+	}
+
+	total := n
+	if isBit {
+		total = n * 8
+	}
+	if start < 0 && end < 0 && start > end {
+		return 0, 0, false, nil
+	}
+	if start < 0 {
+		start = max(total+start, 0)
+	}
+	if end < 0 {
+		end = max(total+end, 0)
+	}
+	end = min(end, total-1)
+	if start > end {
+		return 0, 0, false, nil
+	}
+	if isBit {
+		return start, end, true, nil
+	}
+	return start * 8, end*8 + 7, true, nil
+}
+
+// HandleBitcount counts the bits set in a string, or in a range of it:
+// BITCOUNT key [start end [BYTE|BIT]].
+func HandleBitcount(c *Client, args []string) error {
+	if len(args) == 3 || len(args) > 5 {
+		return errSyntax
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	value, _, err := lookupStringRead(args[1])
+	if err != nil {
+		return err
+	}
+	startBit, endBit := int64(0), int64(len(value))*8-1
+	if len(args) > 2 {
+		var ok bool
+		startBit, endBit, ok, err = parseBitRange(args, 2, int64(len(value)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			c.Reply.Integer(0)
+			return nil
+		}
+	}
+	c.Reply.Integer(countBits(value, startBit, endBit))
+	return nil
+}
+
+// countBits counts the bits set from startBit to endBit in value.
+func countBits(value string, startBit, endBit int64) int64 {
+	if startBit > endBit {
+		return 0
+	}
+	first, last := startBit>>3, endBit>>3
+	var n int
+	for i := first; i <= last; i++ {
+		b := value[i]
+		if i == first {
+			b &= 0xff >> (startBit & 7)
+		}
+		if i == last {
+			b &= 0xff << (7 - endBit&7)
+		}
+		n += bits.OnesCount8(b)
+	}
+	return int64(n)
+}
@@ -0,0 +1,274 @@
+package commands
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// BITFIELD operations and overflow behaviours.
+const (
+	bitfieldGet = iota
+	bitfieldSet
+	bitfieldIncrby
+)
+
+const (
+	overflowWrap = iota
+	overflowSat
+	overflowFail
+)
+
+// bitfieldOp is one GET, SET or INCRBY of a BITFIELD command.
+type bitfieldOp struct {
+	opcode   int
+	signed   bool
+	bits     int
+	offset   int64
+	value    int64 // of SET, or the increment of INCRBY
+	overflow int
+}
+
+func HandleBitfield(c *Client, args []string) error {
+	return bitfieldGeneric(c, args, false)
+}
+
+func HandleBitfieldRo(c *Client, args []string) error {
+	return bitfieldGeneric(c, args, true)
+}
+
+// bitfieldGeneric implements BITFIELD key [GET type offset]
+// [SET type offset value] [INCRBY type offset increment]
+// [OVERFLOW WRAP|SAT|FAIL] ..., which works on integers of any width up
+// to 64 bits at arbitrary bit offsets. A type is i<bits> or u<bits>, and
+// an offset prefixed by "#" counts in fields of that type. OVERFLOW sets
+// what the SET and INCRBY after it do when the result doesn't fit: wrap
+// around (the default), saturate, or fail, replying nil. BITFIELD_RO only
+// accepts GET.
+func bitfieldGeneric(c *Client, args []string, readonly bool) error {
+	var ops []bitfieldOp
+	overflow := overflowWrap
+	writes := false
+	highestBit := int64(-1)
+	for j := 2; j < len(args); j++ {
+		remaining := len(args) - j - 1
+		op := bitfieldOp{overflow: overflow}
+		switch sub := strings.ToUpper(args[j]); {
+		case sub == "GET" && remaining >= 2:
+			op.opcode = bitfieldGet
+		case sub == "SET" && remaining >= 3:
+			op.opcode = bitfieldSet
+		case sub == "INCRBY" && remaining >= 3:
+			op.opcode = bitfieldIncrby
+		case sub == "OVERFLOW" && remaining >= 1:
+			j++
+			switch strings.ToUpper(args[j]) {
+			case "WRAP":
+				overflow = overflowWrap
+			case "SAT":
+				overflow = overflowSat
+			case "FAIL":
+				overflow = overflowFail
+			default:
+				return errors.New("ERR Invalid OVERFLOW type specified")
+			}
+			continue
+		default:
+			return errSyntax
+		}
+
+		var err error
+		if op.signed, op.bits, err = parseBitfieldType(args[j+1]); err != nil {
+			return err
+		}
+		if op.offset, err = parseBitOffset(args[j+2], true, op.bits); err != nil {
+			return err
+		}
+		if op.opcode == bitfieldGet {
+			j += 2
+		} else {
+			if readonly {
+				return errors.New("ERR BITFIELD_RO only supports the GET subcommand")
+			}
+			if op.value, err = strconv.ParseInt(args[j+3], 10, 64); err != nil {
+				return errNotInteger
+			}
+			writes = true
+			highestBit = max(highestBit, op.offset+int64(op.bits)-1)
+			j += 3
+		}
+		ops = append(ops, op)
+	}
+
+	key := args[1]
+	var value string
+	var b []byte
+	var err error
+	changed := false
+	if writes {
+		mu.Lock()
+		defer mu.Unlock()
+		var exists bool
+		if value, exists, err = lookupStringWrite(key); err != nil {
+			return err
+		}
+		b = growForBit(value, highestBit)
+		changed = !exists || len(b) > len(value)
+	} else {
+		mu.RLock()
+		defer mu.RUnlock()
+		if value, _, err = lookupStringRead(key); err != nil {
+			return err
+		}
+		b = []byte(value)
+	}
+
+	c.Reply.ArrayLen(len(ops))
+	for _, op := range ops {
+		old := getBitfield(b, op.offset, op.bits)
+		if op.signed && op.bits < 64 && old&(1<<(op.bits-1)) != 0 {
+			old |= math.MaxUint64 << op.bits // sign extension
+		}
+		if op.opcode == bitfieldGet {
+			c.Reply.Integer(int64(old))
+			continue
+		}
+
+		var newValue uint64
+		var failed bool
+		if op.signed {
+			incr, base := op.value, int64(old)
+			if op.opcode == bitfieldSet {
+				incr, base = 0, op.value
+			}
+			var result int64
+			result, failed = signedBitfieldAdd(base, incr, op.bits, op.overflow)
+			newValue = uint64(result)
+		} else {
+			incr, base := op.value, old
+			if op.opcode == bitfieldSet {
+				incr, base = 0, uint64(op.value)
+			}
+			newValue, failed = unsignedBitfieldAdd(base, incr, op.bits, op.overflow)
+		}
+		if failed {
+			c.Reply.Null()
+			continue
+		}
+
+		setBitfield(b, op.offset, op.bits, newValue)
+		if newValue != old {
+			changed = true
+		}
+		if op.opcode == bitfieldSet {
+			c.Reply.Integer(int64(old))
+		} else {
+			c.Reply.Integer(int64(newValue))
+		}
+	}
+
+	if !changed {
+		preventPropagation(c)
+		return nil
+	}
+	setStringKeepTTL(key, string(b))
+	return nil
+}
+
+// parseBitfieldType parses a BITFIELD type: i1 to i64, or u1 to u63.
+func parseBitfieldType(arg string) (bool, int, error) {
+	errType := errors.New("ERR Invalid bitfield type. Use something like i16 u8. Note that u64 is not supported but i64 is.")
+	if arg == "" || (arg[0] != 'i' && arg[0] != 'u') {
+		return false, 0, errType
+	}
+	signed := arg[0] == 'i'
+	bits, err := strconv.Atoi(arg[1:])
+	if err != nil || bits < 1 || bits > 64 || (!signed && bits > 63) {
+		return false, 0, errType
+	}
+	return signed, bits, nil
+}
+
+// getBitfield reads the bits-wide unsigned integer at offset.
+func getBitfield(b []byte, offset int64, bits int) uint64 {
+	var v uint64
+	for i := int64(0); i < int64(bits); i++ {
+		bit := uint64(0)
+		if j := (offset + i) >> 3; j < int64(len(b)) {
+			bit = uint64(b[j]>>(7-(offset+i)&7)) & 1
+		}
+		v = v<<1 | bit
+	}
+	return v
+}
+
+// setBitfield writes the low bits of v at offset.
+func setBitfield(b []byte, offset int64, bits int, v uint64) {
+	for i := 0; i < bits; i++ {
+		setBit(b, offset+int64(i), int(v>>(bits-1-i))&1)
+	}
+}
+
+// signedBitfieldAdd adds incr to value as bits-wide signed integers,
+// handling an overflow as told. It reports true if it failed.
+func signedBitfieldAdd(value, incr int64, bits, overflow int) (int64, bool) {
+	maxValue := int64(math.MaxInt64)
+	if bits < 64 {
+		maxValue = 1<<(bits-1) - 1
+	}
+	minValue := -maxValue - 1
+	maxIncr, minIncr := maxValue-value, minValue-value
+
+	var limit int64
+	switch {
+	case value > maxValue || (bits != 64 && incr > maxIncr) || (value >= 0 && incr > 0 && incr > maxIncr):
+		limit = maxValue
+	case value < minValue || (bits != 64 && incr < minIncr) || (value < 0 && incr < 0 && incr < minIncr):
+		limit = minValue
+	default:
+		return value + incr, false
+	}
+
+	switch overflow {
+	case overflowSat:
+		return limit, false
+	case overflowFail:
+		return 0, true
+	}
+	// wrap around: keep the low bits, sign extended
+	sum := uint64(value) + uint64(incr)
+	if bits < 64 {
+		if sum&(1<<(bits-1)) != 0 {
+			sum |= math.MaxUint64 << bits
+		} else {
+			sum &^= math.MaxUint64 << bits
+		}
+	}
+	return int64(sum), false
+}
+
+// unsignedBitfieldAdd is signedBitfieldAdd for bits-wide unsigned
+// integers.
+func unsignedBitfieldAdd(value uint64, incr int64, bits, overflow int) (uint64, bool) {
+	maxValue := uint64(1)<<bits - 1
+	maxIncr, minIncr := int64(maxValue-value), -int64(value)
+
+	var limit uint64
+	switch {
+	case value > maxValue || (incr > 0 && incr > maxIncr):
+		limit = maxValue
+	case incr < 0 && incr < minIncr:
+		limit = 0
+	default:
+		return value + uint64(incr), false
+	}
+
+	switch overflow {
+	case overflowSat:
+		return limit, false
+	case overflowFail:
+		return 0, true
+	}
+	return (value + uint64(incr)) &^ (math.MaxUint64 << bits), false
+}
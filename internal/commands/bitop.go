@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+)
+
+// HandleBitop stores the result of a bitwise operation between strings at
+// destkey, replying with its length: BITOP AND|OR|XOR|NOT destkey key
+// [key ...]. Shorter strings are padded with zero bytes.
+func HandleBitop(c *Client, args []string) error {
+	op, destination, keys := strings.ToUpper(args[1]), args[2], args[3:]
+	switch op {
+	case "AND", "OR", "XOR":
+	case "NOT":
+		if len(keys) != 1 {
+			return errors.New("ERR BITOP NOT must be called with a single source key.")
+		}
+	default:
+		return errSyntax
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	values := make([]string, len(keys))
+	size := 0
+	for i, key := range keys {
+		value, _, err := lookupStringRead(key)
+		if err != nil {
+			return err
+		}
+		values[i] = value
+		size = max(size, len(value))
+	}
+
+	result := make([]byte, size)
+	for j := range result {
+		b := byteAt(values[0], j)
+		for _, value := range values[1:] {
+			switch op {
+			case "AND":
+				b &= byteAt(value, j)
+			case "OR":
+				b |= byteAt(value, j)
+			case "XOR":
+				b ^= byteAt(value, j)
+			}
+		}
+		if op == "NOT" {
+			b = ^b
+		}
+		result[j] = b
+	}
+
+	if size == 0 {
+		deleteKey(destination)
+	} else {
+		setKey(destination, core.StoreEntry{Type: "string", Data: string(result)})
+	}
+	c.Reply.Integer(int64(size))
+	return nil
+}
+
+func byteAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return 0
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser"
+)
+
+// Bitmaps are plain strings, read as a sequence of bits starting from the
+// most significant bit of the first byte. Bits past the end of a string
+// read as 0, and writing one grows the string with zero bytes.
+
+var errBitOffset = errors.New("ERR bit offset is not an integer or out of range")
+
+// parseBitOffset parses a bit offset. With hash set (BITFIELD) "#n" stands
+// for the offset of the n-th field of width bits.
+func parseBitOffset(arg string, hash bool, width int) (int64, error) {
+	multiplier := int64(1)
+	if hash && strings.HasPrefix(arg, "#") {
+		arg = arg[1:]
+		multiplier = int64(width)
+	}
+	offset, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || offset < 0 || offset > (1<<62)/multiplier {
+		return 0, errBitOffset
+	}
+	offset *= multiplier
+	// the string holding the bit must stay within proto-max-bulk-len
+	if offset>>3 >= parser.MaxBulkLen() {
+		return 0, errBitOffset
+	}
+	return offset, nil
+}
+
+// growForBit returns value as bytes, with zero bytes appended if needed to
+// hold bit.
+func growForBit(value string, bit int64) []byte {
+	size := max(int(bit>>3)+1, len(value))
+	b := make([]byte, size)
+	copy(b, value)
+	return b
+}
+
+func getBit(value string, bit int64) int {
+	i := bit >> 3
+	if i >= int64(len(value)) {
+		return 0
+	}
+	return int(value[i]>>(7-bit&7)) & 1
+}
+
+func setBit(b []byte, bit int64, on int) {
+	mask := byte(1) << (7 - bit&7)
+	if on == 1 {
+		b[bit>>3] |= mask
+	} else {
+		b[bit>>3] &^= mask
+	}
+}
@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"errors"
+	"strconv"
+)
+
+// HandleBitpos replies with the offset of the first bit set to bit:
+// BITPOS key bit [start [end [BYTE|BIT]]]. Without an end, a string
+// that's all ones has its first clear bit right after its end.
+func HandleBitpos(c *Client, args []string) error {
+	bit, err := strconv.Atoi(args[2])
+	if err != nil || bit&^1 != 0 {
+		return errors.New("ERR The bit argument must be 1 or 0.")
+	}
+	if len(args) > 6 {
+		return errSyntax
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	value, exists, err := lookupStringRead(args[1])
+	if err != nil {
+		return err
+	}
+	if !exists {
+		c.Reply.Integer(int64(bit - 1)) // -1 for a 1, 0 for a 0
+		return nil
+	}
+
+	startBit, endBit := int64(0), int64(len(value))*8-1
+	endGiven := len(args) > 4
+	if len(args) > 3 {
+		var ok bool
+		startBit, endBit, ok, err = parseBitRange(args, 3, int64(len(value)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			c.Reply.Integer(-1)
+			return nil
+		}
+	} else if len(value) == 0 {
+		c.Reply.Integer(-1)
+		return nil
+	}
+
+	pos := findBit(value, bit, startBit, endBit)
+	if pos < 0 && bit == 0 && !endGiven {
+		pos = endBit + 1
+	}
+	c.Reply.Integer(pos)
+	return nil
+}
+
+// findBit returns the offset of the first bit set to bit between startBit
+// and endBit, -1 if there's none. It skips whole bytes that can't have
+// it.
+func findBit(value string, bit int, startBit, endBit int64) int64 {
+	skip := byte(0)
+	if bit == 0 {
+		skip = 0xff
+	}
+	for i := startBit; i <= endBit; {
+		if i&7 == 0 && i+7 <= endBit && value[i>>3] == skip {
+			i += 8
+			continue
+		}
+		if getBit(value, i) == bit {
+			return i
+		}
+		i++
+	}
+	return -1
+}
@@ -0,0 +1,46 @@
+package commands
+
+import "strings"
+
+// HandleBlmove is LMOVE that waits for the source list to get an element:
+// BLMOVE source destination LEFT|RIGHT LEFT|RIGHT timeout.
+func HandleBlmove(c *Client, args []string) error {
+	from, err := parseWhere(args[3])
+	if err != nil {
+		return err
+	}
+	to, err := parseWhere(args[4])
+	if err != nil {
+		return err
+	}
+	return blockingMoveGeneric(c, args[1], args[2], from, to, args[5])
+}
+
+// HandleBrpoplpush is BLMOVE source destination RIGHT LEFT timeout.
+func HandleBrpoplpush(c *Client, args []string) error {
+	return blockingMoveGeneric(c, args[1], args[2], "right", "left", args[3])
+}
+
+func blockingMoveGeneric(c *Client, src, dst, from, to, timeoutArg string) error {
+	timeout, err := parseBlockTimeout(timeoutArg)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	value, ok, err := listMove(src, dst, from, to)
+	if err != nil {
+		return err
+	}
+	if ok {
+		propagateAs(c, "LMOVE", src, dst, strings.ToUpper(from), strings.ToUpper(to))
+		c.Reply.Bulk(value)
+		return nil
+	}
+
+	return blockForKeys([]string{src}, "list", timeout, func(c *Client) {
+		c.Reply.Null()
+	})
+}
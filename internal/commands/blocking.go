@@ -0,0 +1,230 @@
+package commands
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Blocking commands (BLPOP, BLMOVE, XREAD BLOCK, ...) don't wait in their
+// handlers. A handler that finds nothing to serve returns blockForKeys'
+// error instead of replying, and the dispatcher parks the client on those
+// keys. Whatever may make a key servable calls signalKeyAsReady (setKey
+// does for every key it stores), and once that command is done
+// handleClientsBlockedOnKeys runs the blocked commands again on behalf of
+// their clients, in the order they blocked. One that still finds nothing
+// just stays blocked, keeping its place in line.
+
+// blockRequest is the error a blocking command's handler returns when it
+// has to wait. It never reaches the client.
+type blockRequest struct {
+	keys      []string
+	keyType   string
+	timeout   time.Duration // 0 waits forever
+	onTimeout func(c *Client)
+}
+
+func (b *blockRequest) Error() string {
+	return "blocked"
+}
+
+// blockForKeys makes the running command wait until one of keys is
+// signalled while holding a value of keyType, then run again. If that
+// doesn't happen within timeout, onTimeout replies instead. Inside MULTI,
+// or for commands from our master, it replies as if the timeout had passed
+// right away.
+func blockForKeys(keys []string, keyType string, timeout time.Duration, onTimeout func(c *Client)) error {
+	return &blockRequest{keys: keys, keyType: keyType, timeout: timeout, onTimeout: onTimeout}
+}
+
+type blockedClient struct {
+	c       *Client
+	cmd     *Command
+	args    []string
+	keys    []string
+	keyType string
+
+	mu     sync.Mutex // held while the command is run again
+	done   bool
+	served chan struct{}
+}
+
+var (
+	blockedMu   sync.Mutex
+	blockedKeys = make(map[string][]*blockedClient) // in the order they blocked
+	readyKeys   []string
+	readySet    = make(map[string]bool)
+)
+
+// signalKeyAsReady notes that key may now be able to serve the clients
+// blocked on it.
+func signalKeyAsReady(key string) {
+	blockedMu.Lock()
+	defer blockedMu.Unlock()
+
+	if len(blockedKeys[key]) == 0 || readySet[key] {
+		return
+	}
+	readySet[key] = true
+	readyKeys = append(readyKeys, key)
+}
+
+// blockClient parks c until its command is served, times out, or the
+// client disconnects.
+func blockClient(c *Client, cmd *Command, args []string, req *blockRequest) {
+	// replies to earlier pipelined commands shouldn't wait with us, and
+	// once we're registered c.Reply may be written on our behalf
+	c.Reply.Flush()
+
+	bc := &blockedClient{c: c, cmd: cmd, args: args, keyType: req.keyType, served: make(chan struct{})}
+	for _, key := range req.keys {
+		if !slices.Contains(bc.keys, key) {
+			bc.keys = append(bc.keys, key)
+		}
+	}
+
+	blockedMu.Lock()
+	for _, key := range bc.keys {
+		blockedKeys[key] = append(blockedKeys[key], bc)
+	}
+	blockedMu.Unlock()
+
+	// one of the keys may have been filled since the handler looked
+	if serveBlockedClient(bc, bc.keys) {
+		return
+	}
+
+	var timeout <-chan time.Time
+	if req.timeout > 0 {
+		timer := time.NewTimer(req.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-bc.served:
+		return
+	case <-timeout:
+	case <-c.Closed:
+	}
+
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	if bc.done {
+		return // served just before the timeout
+	}
+	bc.done = true
+	unblockClient(bc)
+	req.onTimeout(c) // after a disconnect this goes nowhere
+}
+
+// serveBlockedClient runs a blocked command again if one of the ready
+// keys holds the type of value it waits for, reporting whether it got
+// served. Like Redis, a key that now holds something else, e.g. a string
+// SET over the list a BLPOP waits for, leaves the client blocked rather
+// than failing it.
+func serveBlockedClient(bc *blockedClient, ready []string) bool {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	if bc.done {
+		return false
+	}
+
+	execMu.RLock()
+	defer execMu.RUnlock()
+	if !holdsType(ready, bc.keyType) {
+		return false
+	}
+
+	c := bc.c
+	c.Propagate = nil
+	err := bc.cmd.Handler(c, bc.args)
+
+	var block *blockRequest
+	if errors.As(err, &block) {
+		return false
+	}
+	if err != nil {
+		c.Reply.Error(err.Error())
+	} else if bc.cmd.has(flagWrite) {
+		propagate(c, bc.args)
+	}
+
+	bc.done = true
+	unblockClient(bc)
+	close(bc.served)
+	return true
+}
+
+// handleClientsBlockedOnKeys serves the clients blocked on keys signalled
+// as ready, until serving them doesn't make any more keys ready.
+func handleClientsBlockedOnKeys() {
+	for {
+		blockedMu.Lock()
+		keys := readyKeys
+		readyKeys = nil
+		clear(readySet)
+		blockedMu.Unlock()
+
+		if len(keys) == 0 {
+			return
+		}
+
+		for _, key := range keys {
+			blockedMu.Lock()
+			queue := slices.Clone(blockedKeys[key])
+			blockedMu.Unlock()
+
+			for _, bc := range queue {
+				serveBlockedClient(bc, []string{key})
+			}
+		}
+	}
+}
+
+// holdsType reports whether one of keys holds a value of type keyType.
+func holdsType(keys []string, keyType string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, key := range keys {
+		if entry, exists := store.Get(key); exists && entry.Type == keyType {
+			return true
+		}
+	}
+	return false
+}
+
+func unblockClient(bc *blockedClient) {
+	blockedMu.Lock()
+	defer blockedMu.Unlock()
+
+	for _, key := range bc.keys {
+		queue := slices.DeleteFunc(blockedKeys[key], func(other *blockedClient) bool {
+			return other == bc
+		})
+		if len(queue) == 0 {
+			delete(blockedKeys, key)
+		} else {
+			blockedKeys[key] = queue
+		}
+	}
+}
+
+// parseBlockTimeout parses the timeout of BLPOP & co., given in seconds
+// with an optional fraction.
+func parseBlockTimeout(arg string) (time.Duration, error) {
+	seconds, err := parseFloat(arg)
+	if err != nil {
+		return 0, errors.New("ERR timeout is not a float or out of range")
+	}
+	if seconds < 0 {
+		return 0, errors.New("ERR timeout is negative")
+	}
+	if seconds > float64(math.MaxInt64/time.Second) {
+		return 0, errors.New("ERR timeout is out of range")
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
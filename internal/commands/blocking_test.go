@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBlockedClientIgnoresWrongType(t *testing.T) {
+	blocked, other := newTestClient(), newTestClient()
+	replies := make(chan string, 1)
+	go func() {
+		replies <- blocked.do("BLPOP", "blocking:wrongtype", "0")
+	}()
+	waitBlocked(t, "blocking:wrongtype")
+
+	if got := other.do("SET", "blocking:wrongtype", "str"); got != "+OK\r\n" {
+		t.Fatalf("SET = %q", got)
+	}
+	select {
+	case got := <-replies:
+		t.Fatalf("BLPOP served by a string with %q", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	other.do("DEL", "blocking:wrongtype")
+	other.do("RPUSH", "blocking:wrongtype", "v")
+	select {
+	case got := <-replies:
+		if want := "*2\r\n$18\r\nblocking:wrongtype\r\n$1\r\nv\r\n"; got != want {
+			t.Fatalf("BLPOP = %q, want %q", got, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("BLPOP not served by a list")
+	}
+}
+
+func TestMpopHugeNumkeys(t *testing.T) {
+	c := newTestClient()
+	for _, args := range [][]string{
+		{"LMPOP", "9223372036854775807", "a", "LEFT"},
+		{"BLMPOP", "0", "9223372036854775807", "a", "LEFT"},
+		{"BLMPOP", "0", "2", "a", "LEFT"},
+	} {
+		if got := c.do(args...); got != "-ERR syntax error\r\n" {
+			t.Errorf("%v = %q, want a syntax error", args, got)
+		}
+	}
+	for _, args := range [][]string{
+		{"COMMAND", "GETKEYS", "LMPOP", "9223372036854775807", "a", "LEFT"},
+		{"COMMAND", "GETKEYS", "BLMPOP", "0", "9223372036854775807", "a", "LEFT"},
+		{"COMMAND", "GETKEYS", "SINTERCARD", "9223372036854775807", "a"},
+		{"COMMAND", "GETKEYS", "ZUNIONSTORE", "d", "9223372036854775807", "a"},
+	} {
+		if got := c.do(args...); !strings.HasPrefix(got, "-ERR") {
+			t.Errorf("%v = %q, want an error", args, got)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"strings"
+)
+
+func HandleBlpop(c *Client, args []string) error {
+	return blockingPopGeneric(c, args, "left")
+}
+
+func HandleBrpop(c *Client, args []string) error {
+	return blockingPopGeneric(c, args, "right")
+}
+
+// blockingPopGeneric implements BLPOP/BRPOP key [key ...] timeout: pop
+// from the first non-empty list, replying with [key, element], or wait for
+// one of them to get an element.
+func blockingPopGeneric(c *Client, args []string, where string) error {
+	keys := args[1 : len(args)-1]
+	timeout, err := parseBlockTimeout(args[len(args)-1])
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, key := range keys {
+		list, err := lookupListWrite(key)
+		if err != nil {
+			return err
+		}
+		if list == nil {
+			continue
+		}
+
+		value, _ := listPop(list, where)
+		signalModifiedKey(key)
+		deleteListIfEmpty(key, list)
+		propagateAs(c, strings.ToUpper(where[:1])+"POP", key)
+		c.Reply.BulkArray([]string{key, value})
+		return nil
+	}
+
+	return blockForKeys(keys, "list", timeout, func(c *Client) {
+		c.Reply.NullArray()
+	})
+}
+
+// HandleBlmpop is LMPOP with a timeout to wait for one of the lists:
+// BLMPOP timeout numkeys key [key ...] LEFT|RIGHT [COUNT count].
+func HandleBlmpop(c *Client, args []string) error {
+	timeout, err := parseBlockTimeout(args[1])
+	if err != nil {
+		return err
+	}
+	keys, where, count, err := parseMpopArgs(args, 2)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	served, err := listMpop(c, keys, where, count)
+	if err != nil || served {
+		return err
+	}
+
+	return blockForKeys(keys, "list", timeout, func(c *Client) {
+		c.Reply.NullArray()
+	})
+}
+
+// blmpopKeys finds the keys of BLMPOP for COMMAND GETKEYS.
+func blmpopKeys(args []string) []int {
+	return numkeysKeys(args, 2)
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"errors"
+
+	models "github.com/codecrafters-io/redis-starter-go/internal/models/core"
+)
+
+func HandleClientID(c *Client, args []string) error {
+	c.Reply.Integer(c.ID)
+	return nil
+}
+
+func HandleClientGetName(c *Client, args []string) error {
+	models.ClientMu.Lock()
+	name := c.Name
+	models.ClientMu.Unlock()
+
+	if name == "" {
+		c.Reply.Null()
+		return nil
+	}
+	c.Reply.Bulk(name)
+	return nil
+}
+
+func HandleClientSetName(c *Client, args []string) error {
+	if err := setClientName(c, args[2]); err != nil {
+		return err
+	}
+
+	c.Reply.OK()
+	return nil
+}
+
+// setClientName validates and stores a connection name; an empty name
+// clears it.
+func setClientName(c *Client, name string) error {
+	for _, ch := range name {
+		if ch <= ' ' || ch > '~' {
+			return errors.New("ERR Client names cannot contain spaces, newlines or special characters.")
+		}
+	}
+
+	models.ClientMu.Lock()
+	c.Name = name
+	models.ClientMu.Unlock()
+	return nil
+}
@@ -0,0 +1,313 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/resp"
+	"github.com/codecrafters-io/redis-starter-go/internal/utils"
+)
+
+func HandleCommand(c *Client, args []string) error {
+	cmds := sortedCommands()
+
+	c.Reply.ArrayLen(len(cmds))
+	for _, cmd := range cmds {
+		writeCommandInfo(c.Reply, cmd)
+	}
+	return nil
+}
+
+func HandleCommandCount(c *Client, args []string) error {
+	c.Reply.Integer(int64(len(commandTable)))
+	return nil
+}
+
+func HandleCommandList(c *Client, args []string) error {
+	match := func(cmd *Command) bool { return true }
+	if len(args) > 2 {
+		if len(args) != 5 || !strings.EqualFold(args[2], "filterby") {
+			return errSyntax
+		}
+		value := args[4]
+		switch strings.ToLower(args[3]) {
+		case "module": // there are no modules
+			match = func(cmd *Command) bool { return false }
+		case "aclcat":
+			category := "@" + strings.ToLower(value)
+			match = func(cmd *Command) bool {
+				for _, cat := range cmd.aclCategories() {
+					if cat == category {
+						return true
+					}
+				}
+				return false
+			}
+		case "pattern":
+			match = func(cmd *Command) bool { return utils.GlobMatch(value, cmd.FullName(), true) }
+		default:
+			return errSyntax
+		}
+	}
+
+	var names []string
+	for _, cmd := range sortedCommands() {
+		if match(cmd) {
+			names = append(names, cmd.Name)
+		}
+		for _, sub := range cmd.Subcommands {
+			if match(sub) {
+				names = append(names, sub.FullName())
+			}
+		}
+	}
+
+	c.Reply.BulkArray(names)
+	return nil
+}
+
+func HandleCommandInfo(c *Client, args []string) error {
+	if len(args) == 2 {
+		return HandleCommand(c, args)
+	}
+
+	c.Reply.ArrayLen(len(args) - 2)
+	for _, name := range args[2:] {
+		cmd, ok := lookupCommandByFullName(name)
+		if !ok {
+			c.Reply.Null()
+			continue
+		}
+		writeCommandInfo(c.Reply, cmd)
+	}
+	return nil
+}
+
+func HandleCommandDocs(c *Client, args []string) error {
+	var cmds []*Command
+	if len(args) == 2 {
+		cmds = sortedCommands()
+	} else {
+		for _, name := range args[2:] {
+			if cmd, ok := lookupCommandByFullName(name); ok {
+				cmds = append(cmds, cmd)
+			}
+		}
+	}
+
+	c.Reply.MapLen(len(cmds))
+	for _, cmd := range cmds {
+		c.Reply.Bulk(cmd.FullName())
+		writeCommandDocs(c.Reply, cmd)
+	}
+	return nil
+}
+
+func HandleCommandGetKeys(c *Client, args []string) error {
+	target := args[2:]
+
+	cmd, ok := lookupCommand(target[0])
+	if !ok {
+		return errors.New("ERR Invalid command specified")
+	}
+	if len(cmd.Subcommands) > 0 && len(target) > 1 {
+		if sub, ok := cmd.subcommand(target[1]); ok {
+			cmd = sub
+		}
+	}
+	if !cmd.checkArity(len(target)) {
+		return errors.New("ERR Invalid number of arguments specified for command")
+	}
+
+	positions := cmd.keyPositions(target)
+	if len(positions) == 0 {
+		return errors.New("ERR The command has no key arguments")
+	}
+
+	c.Reply.ArrayLen(len(positions))
+	for _, pos := range positions {
+		c.Reply.Bulk(target[pos])
+	}
+	return nil
+}
+
+// lookupCommandByFullName resolves both "get" and "config|get".
+func lookupCommandByFullName(name string) (*Command, bool) {
+	container, subName, isSub := strings.Cut(name, "|")
+	cmd, ok := lookupCommand(container)
+	if !ok || !isSub {
+		return cmd, ok
+	}
+	return cmd.subcommand(subName)
+}
+
+// writeCommandInfo writes the 10 element COMMAND INFO reply for cmd: name,
+// arity, flags, first key, last key, step, ACL categories, tips, key specs
+// and subcommands.
+func writeCommandInfo(w *resp.Writer, cmd *Command) {
+	w.ArrayLen(10)
+	w.Bulk(cmd.FullName())
+	w.Integer(int64(cmd.Arity))
+
+	flags := cmd.flagNames()
+	w.SetLen(len(flags))
+	for _, flag := range flags {
+		w.SimpleString(flag)
+	}
+
+	w.Integer(int64(cmd.FirstKey))
+	w.Integer(int64(cmd.LastKey))
+	w.Integer(int64(cmd.Step))
+
+	categories := cmd.aclCategories()
+	w.SetLen(len(categories))
+	for _, category := range categories {
+		w.SimpleString(category)
+	}
+
+	w.ArrayLen(0) // tips
+	writeKeySpecs(w, cmd)
+
+	w.ArrayLen(len(cmd.Subcommands))
+	for _, sub := range cmd.Subcommands {
+		writeCommandInfo(w, sub)
+	}
+}
+
+// writeKeySpecs describes the command's keys in the Redis 7 key-spec
+// format. Commands with fixed positions get an index/range spec; commands
+// with a KeysFunc are reported as "unknown" so clients fall back to GETKEYS.
+func writeKeySpecs(w *resp.Writer, cmd *Command) {
+	if cmd.FirstKey == 0 && cmd.KeysFunc == nil {
+		w.ArrayLen(0)
+		return
+	}
+
+	access := []string{"RO", "ACCESS"}
+	if cmd.has(flagWrite) {
+		access = []string{"RW", "UPDATE"}
+	}
+
+	w.ArrayLen(1)
+	w.MapLen(3)
+	w.Bulk("flags")
+	w.SetLen(len(access))
+	for _, flag := range access {
+		w.SimpleString(flag)
+	}
+
+	if cmd.KeysFunc != nil {
+		for _, section := range []string{"begin_search", "find_keys"} {
+			w.Bulk(section)
+			w.MapLen(2)
+			w.Bulk("type")
+			w.Bulk("unknown")
+			w.Bulk("spec")
+			w.MapLen(0)
+		}
+		return
+	}
+
+	lastKey := cmd.LastKey
+	if lastKey >= 0 {
+		lastKey -= cmd.FirstKey
+	}
+
+	w.Bulk("begin_search")
+	w.MapLen(2)
+	w.Bulk("type")
+	w.Bulk("index")
+	w.Bulk("spec")
+	w.MapLen(1)
+	w.Bulk("index")
+	w.Integer(int64(cmd.FirstKey))
+
+	w.Bulk("find_keys")
+	w.MapLen(2)
+	w.Bulk("type")
+	w.Bulk("range")
+	w.Bulk("spec")
+	w.MapLen(3)
+	w.Bulk("lastkey")
+	w.Integer(int64(lastKey))
+	w.Bulk("keystep")
+	w.Integer(int64(cmd.Step))
+	w.Bulk("limit")
+	w.Integer(0)
+}
+
+// writeCommandDocs writes the COMMAND DOCS map for a single command.
+func writeCommandDocs(w *resp.Writer, cmd *Command) {
+	fields := [][2]string{
+		{"summary", cmd.Summary},
+		{"since", cmd.Since},
+		{"group", cmd.Group},
+		{"complexity", cmd.Complexity},
+	}
+
+	n := len(fields)
+	if len(cmd.Subcommands) > 0 {
+		n++
+	}
+	w.MapLen(n)
+	for _, field := range fields {
+		w.Bulk(field[0])
+		w.Bulk(field[1])
+	}
+
+	if len(cmd.Subcommands) > 0 {
+		w.Bulk("subcommands")
+		w.MapLen(len(cmd.Subcommands))
+		for _, sub := range cmd.Subcommands {
+			w.Bulk(sub.FullName())
+			writeCommandDocs(w, sub)
+		}
+	}
+}
+func (c *Command) flagNames() []string {
+	var names []string
+	for _, f := range flagNames {
+		if c.has(f.flag) {
+			names = append(names, f.name)
+		}
+	}
+	if c.KeysFunc != nil {
+		names = append(names, "movablekeys")
+	}
+	return names
+}
+
+// aclCategories derives the ACL categories from the command's flags and
+// group, the same way Redis does for commands without explicit categories.
+func (c *Command) aclCategories() []string {
+	var categories []string
+	if c.has(flagWrite) {
+		categories = append(categories, "@write")
+	}
+	if c.has(flagReadonly) {
+		categories = append(categories, "@read")
+	}
+	if c.has(flagAdmin) {
+		categories = append(categories, "@admin", "@dangerous")
+	}
+	if c.has(flagPubsub) {
+		categories = append(categories, "@pubsub")
+	}
+	if c.has(flagBlocking) {
+		categories = append(categories, "@blocking")
+	}
+
+	switch c.Group {
+	case "generic":
+		if c.FirstKey != 0 || c.KeysFunc != nil {
+			categories = append(categories, "@keyspace")
+		}
+	case "transactions":
+		categories = append(categories, "@transaction")
+	case "sorted-set":
+		categories = append(categories, "@sortedset")
+	case "string", "list", "set", "hash", "stream", "bitmap", "hyperloglog", "geo", "connection":
+		categories = append(categories, "@"+c.Group)
+	}
+	return categories
+}
@@ -0,0 +1,1264 @@
+package commands
+
+import (
+	"sort"
+	"strings"
+)
+
+type commandFlag uint32
+
+const (
+	flagWrite    commandFlag = 1 << iota // modifies the keyspace, propagated to replicas
+	flagReadonly                         // only reads from the keyspace
+	flagBlocking                         // may block the client
+	flagAdmin                            // administrative / replication command
+	flagPubsub                           // pub/sub related
+	flagNoScript                         // not allowed from scripts
+	flagLoading                          // allowed while the dataset is loading
+	flagNoMulti                          // not allowed inside MULTI
+	flagNoAuth                           // allowed before the client authenticates
+)
+
+// flagNames lists the flags in the order COMMAND reports them.
+var flagNames = []struct {
+	flag commandFlag
+	name string
+}{
+	{flagWrite, "write"},
+	{flagReadonly, "readonly"},
+	{flagAdmin, "admin"},
+	{flagPubsub, "pubsub"},
+	{flagNoScript, "noscript"},
+	{flagBlocking, "blocking"},
+	{flagLoading, "loading"},
+	{flagNoAuth, "no_auth"},
+	{flagNoMulti, "no_multi"},
+}
+
+// commandFunc executes a command whose arity has already been checked. A
+// returned error is sent to the client as-is (so it must carry its error
+// code, e.g. "ERR ..." or "WRONGTYPE ...") and stops the command from being
+// propagated to replicas.
+type commandFunc func(c *Client, args []string) error
+
+// Command describes a single command: its arity (negative means "at least"),
+// flags, key positions and handler. Key positions follow the Redis
+// convention: first and last are argument indexes (last may be negative,
+// counting from the end) and step is the distance between keys; all zero
+// means the command takes no keys. Commands whose keys can't be described
+// that way (XREAD ... STREAMS k1 k2 id1 id2) provide KeysFunc instead.
+//
+// A command with Subcommands is a container (CONFIG, COMMAND): args[1]
+// selects the subcommand, whose arity counts the container name too. The
+// container's own Handler, if any, runs when no subcommand is given.
+type Command struct {
+	Name     string
+	Arity    int
+	Flags    commandFlag
+	FirstKey int
+	LastKey  int
+	Step     int
+	KeysFunc func(args []string) []int
+	Handler  commandFunc
+
+	// documentation reported by COMMAND DOCS
+	Group      string
+	Since      string
+	Summary    string
+	Complexity string
+
+	Subcommands []*Command
+	parent      *Command
+}
+
+var commandTable = make(map[string]*Command)
+
+func init() {
+	registerCommands(
+		&Command{
+			Name: "ping", Arity: -1,
+			Group: "connection", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Returns the server's liveliness response.",
+			Handler: HandlePing,
+		},
+		&Command{
+			Name: "echo", Arity: 2,
+			Group: "connection", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Returns the given string.",
+			Handler: HandleEcho,
+		},
+		&Command{
+			Name: "hello", Arity: -1, Flags: flagNoScript | flagLoading | flagNoAuth,
+			Group: "connection", Since: "6.0.0", Complexity: "O(1)",
+			Summary: "Handshakes with the Redis server.",
+			Handler: HandleHello,
+		},
+		&Command{
+			Name: "auth", Arity: -2, Flags: flagNoScript | flagLoading | flagNoAuth,
+			Group: "connection", Since: "1.0.0", Complexity: "O(N) where N is the number of passwords defined for the user",
+			Summary: "Authenticates the connection.",
+			Handler: HandleAuth,
+		},
+		&Command{
+			Name: "client", Arity: -2,
+			Group: "connection", Since: "2.4.0", Complexity: "Depends on subcommand.",
+			Summary: "A container for client connection commands.",
+			Subcommands: []*Command{
+				{
+					Name: "id", Arity: 2, Flags: flagNoScript | flagLoading,
+					Group: "connection", Since: "5.0.0", Complexity: "O(1)",
+					Summary: "Returns the unique client ID of the connection.",
+					Handler: HandleClientID,
+				},
+				{
+					Name: "getname", Arity: 2, Flags: flagNoScript | flagLoading,
+					Group: "connection", Since: "2.6.9", Complexity: "O(1)",
+					Summary: "Returns the name of the connection.",
+					Handler: HandleClientGetName,
+				},
+				{
+					Name: "setname", Arity: 3, Flags: flagNoScript | flagLoading,
+					Group: "connection", Since: "2.6.9", Complexity: "O(1)",
+					Summary: "Sets the connection name.",
+					Handler: HandleClientSetName,
+				},
+			},
+		},
+		&Command{
+			Name: "set", Arity: -3, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "string", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Sets the string value of a key, ignoring its type. The key is created if it doesn't exist.",
+			Handler: HandleSet,
+		},
+		&Command{
+			Name: "get", Arity: 2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "string", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Returns the string value of a key.",
+			Handler: HandleGet,
+		},
+		&Command{
+			Name: "incr", Arity: 2, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "string", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Increments the integer value of a key by one. Uses 0 as initial value if the key doesn't exist.",
+			Handler: HandleIncr,
+		},
+		&Command{
+			Name: "decr", Arity: 2, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "string", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Decrements the integer value of a key by one. Uses 0 as initial value if the key doesn't exist.",
+			Handler: HandleDecr,
+		},
+		&Command{
+			Name: "incrby", Arity: 3, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "string", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Increments the integer value of a key by a number. Uses 0 as initial value if the key doesn't exist.",
+			Handler: HandleIncrby,
+		},
+		&Command{
+			Name: "decrby", Arity: 3, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "string", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Decrements a number from the integer value of a key. Uses 0 as initial value if the key doesn't exist.",
+			Handler: HandleDecrby,
+		},
+		&Command{
+			Name: "incrbyfloat", Arity: 3, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "string", Since: "2.6.0", Complexity: "O(1)",
+			Summary: "Increment the floating point value of a key by a number. Uses 0 as initial value if the key doesn't exist.",
+			Handler: HandleIncrbyfloat,
+		},
+		&Command{
+			Name: "append", Arity: 3, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "string", Since: "2.0.0", Complexity: "O(1). The amortized time complexity is O(1) assuming the appended value is small and the already present value is of any size, since the dynamic string library used by Redis will double the free space available on every reallocation.",
+			Summary: "Appends a string to the value of a key. Creates the key if it doesn't exist.",
+			Handler: HandleAppend,
+		},
+		&Command{
+			Name: "strlen", Arity: 2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "string", Since: "2.2.0", Complexity: "O(1)",
+			Summary: "Returns the length of a string value.",
+			Handler: HandleStrlen,
+		},
+		&Command{
+			Name: "getrange", Arity: 4, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "string", Since: "2.4.0", Complexity: "O(N) where N is the length of the returned string. The complexity is ultimately determined by the returned length, but because creating a substring from an existing string is very cheap, it can be considered O(1) for small strings.",
+			Summary: "Returns a substring of the string stored at a key.",
+			Handler: HandleGetrange,
+		},
+		&Command{
+			Name: "setrange", Arity: 4, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "string", Since: "2.2.0", Complexity: "O(1), not counting the time taken to copy the new string in place. Usually, this string is very small so the amortized complexity is O(1). Otherwise, complexity is O(M) with M being the length of the value argument.",
+			Summary: "Overwrites a part of a string value with another by an offset. Creates the key if it doesn't exist.",
+			Handler: HandleSetrange,
+		},
+		&Command{
+			Name: "getdel", Arity: 2, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "string", Since: "6.2.0", Complexity: "O(1)",
+			Summary: "Returns the string value of a key after deleting the key.",
+			Handler: HandleGetdel,
+		},
+		&Command{
+			Name: "getex", Arity: -2, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "string", Since: "6.2.0", Complexity: "O(1)",
+			Summary: "Returns the string value of a key after setting its expiration time.",
+			Handler: HandleGetex,
+		},
+		&Command{
+			Name: "mget", Arity: -2, Flags: flagReadonly, FirstKey: 1, LastKey: -1, Step: 1,
+			Group: "string", Since: "1.0.0", Complexity: "O(N) where N is the number of keys to retrieve.",
+			Summary: "Atomically returns the string values of one or more keys.",
+			Handler: HandleMget,
+		},
+		&Command{
+			Name: "mset", Arity: -3, Flags: flagWrite, FirstKey: 1, LastKey: -1, Step: 2,
+			Group: "string", Since: "1.0.1", Complexity: "O(N) where N is the number of keys to set.",
+			Summary: "Atomically creates or modifies the string values of one or more keys.",
+			Handler: HandleMset,
+		},
+		&Command{
+			Name: "msetnx", Arity: -3, Flags: flagWrite, FirstKey: 1, LastKey: -1, Step: 2,
+			Group: "string", Since: "1.0.1", Complexity: "O(N) where N is the number of keys to set.",
+			Summary: "Atomically modifies the string values of one or more keys only when all keys don't exist.",
+			Handler: HandleMsetnx,
+		},
+		&Command{
+			Name: "setnx", Arity: 3, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "string", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Set the string value of a key only when the key doesn't exist.",
+			Handler: HandleSetnx,
+		},
+		&Command{
+			Name: "setex", Arity: 4, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "string", Since: "2.0.0", Complexity: "O(1)",
+			Summary: "Sets the string value and expiration time of a key. Creates the key if it doesn't exist.",
+			Handler: HandleSetex,
+		},
+		&Command{
+			Name: "psetex", Arity: 4, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "string", Since: "2.6.0", Complexity: "O(1)",
+			Summary: "Sets both string value and expiration time in milliseconds of a key. The key is created if it doesn't exist.",
+			Handler: HandlePsetex,
+		},
+		&Command{
+			Name: "lcs", Arity: -3, Flags: flagReadonly, FirstKey: 1, LastKey: 2, Step: 1,
+			Group: "string", Since: "7.0.0", Complexity: "O(N*M) where N and M are the lengths of s1 and s2, respectively",
+			Summary: "Finds the longest common substring.",
+			Handler: HandleLcs,
+		},
+		&Command{
+			Name: "setbit", Arity: 4, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "bitmap", Since: "2.2.0", Complexity: "O(1)",
+			Summary: "Sets or clears the bit at offset of the string value. Creates the key if it doesn't exist.",
+			Handler: HandleSetbit,
+		},
+		&Command{
+			Name: "getbit", Arity: 3, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "bitmap", Since: "2.2.0", Complexity: "O(1)",
+			Summary: "Returns a bit value by offset.",
+			Handler: HandleGetbit,
+		},
+		&Command{
+			Name: "bitcount", Arity: -2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "bitmap", Since: "2.6.0", Complexity: "O(N)",
+			Summary: "Counts the number of set bits (population counting) in a string.",
+			Handler: HandleBitcount,
+		},
+		&Command{
+			Name: "bitpos", Arity: -3, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "bitmap", Since: "2.8.7", Complexity: "O(N)",
+			Summary: "Finds the first set (1) or clear (0) bit in a string.",
+			Handler: HandleBitpos,
+		},
+		&Command{
+			Name: "bitop", Arity: -4, Flags: flagWrite, FirstKey: 2, LastKey: -1, Step: 1,
+			Group: "bitmap", Since: "2.6.0", Complexity: "O(N)",
+			Summary: "Performs bitwise operations on multiple strings, and stores the result.",
+			Handler: HandleBitop,
+		},
+		&Command{
+			Name: "bitfield", Arity: -2, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "bitmap", Since: "3.2.0", Complexity: "O(1) for each subcommand specified",
+			Summary: "Performs arbitrary bitfield integer operations on strings.",
+			Handler: HandleBitfield,
+		},
+		&Command{
+			Name: "bitfield_ro", Arity: -2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "bitmap", Since: "6.0.0", Complexity: "O(1) for each subcommand specified",
+			Summary: "Performs arbitrary read-only bitfield integer operations on strings.",
+			Handler: HandleBitfieldRo,
+		},
+		&Command{
+			Name: "pfadd", Arity: -2, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hyperloglog", Since: "2.8.9", Complexity: "O(1) to add every element.",
+			Summary: "Adds elements to a HyperLogLog key. Creates the key if it doesn't exist.",
+			Handler: HandlePfadd,
+		},
+		&Command{
+			Name: "pfcount", Arity: -2, Flags: flagReadonly, FirstKey: 1, LastKey: -1, Step: 1,
+			Group: "hyperloglog", Since: "2.8.9", Complexity: "O(1) with a very small average constant time when called with a single key. O(N) with N being the number of keys, and much bigger constant times, when called with multiple keys.",
+			Summary: "Returns the approximated cardinality of the set(s) observed by the HyperLogLog key(s).",
+			Handler: HandlePfcount,
+		},
+		&Command{
+			Name: "pfmerge", Arity: -2, Flags: flagWrite, FirstKey: 1, LastKey: -1, Step: 1,
+			Group: "hyperloglog", Since: "2.8.9", Complexity: "O(N) to merge N HyperLogLogs, but with high constant times.",
+			Summary: "Merges one or more HyperLogLog values into a single key.",
+			Handler: HandlePfmerge,
+		},
+		&Command{
+			Name: "geoadd", Arity: -5, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "geo", Since: "3.2.0", Complexity: "O(log(N)) for each item added, where N is the number of elements in the sorted set.",
+			Summary: "Adds one or more members to a geospatial index. The key is created if it doesn't exist.",
+			Handler: HandleGeoadd,
+		},
+		&Command{
+			Name: "geodist", Arity: -4, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "geo", Since: "3.2.0", Complexity: "O(1)",
+			Summary: "Returns the distance between two members of a geospatial index.",
+			Handler: HandleGeodist,
+		},
+		&Command{
+			Name: "geopos", Arity: -2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "geo", Since: "3.2.0", Complexity: "O(1) for each member requested.",
+			Summary: "Returns the longitude and latitude of members from a geospatial index.",
+			Handler: HandleGeopos,
+		},
+		&Command{
+			Name: "geohash", Arity: -2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "geo", Since: "3.2.0", Complexity: "O(1) for each member requested.",
+			Summary: "Returns members from a geospatial index as geohash strings.",
+			Handler: HandleGeohash,
+		},
+		&Command{
+			Name: "geosearch", Arity: -7, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "geo", Since: "6.2.0", Complexity: "O(N+log(M)) where N is the number of elements in the grid-aligned bounding box area around the shape provided as the filter and M is the number of items inside the shape",
+			Summary: "Queries a geospatial index for members inside an area of a box or a circle.",
+			Handler: HandleGeosearch,
+		},
+		&Command{
+			Name: "geosearchstore", Arity: -8, Flags: flagWrite, FirstKey: 1, LastKey: 2, Step: 1,
+			Group: "geo", Since: "6.2.0", Complexity: "O(N+log(M)) where N is the number of elements in the grid-aligned bounding box area around the shape provided as the filter and M is the number of items inside the shape",
+			Summary: "Queries a geospatial index for members inside an area of a box or a circle, optionally stores the result.",
+			Handler: HandleGeosearchstore,
+		},
+		&Command{
+			Name: "type", Arity: 2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "generic", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Determines the type of value stored at a key.",
+			Handler: HandleType,
+		},
+		&Command{
+			Name: "keys", Arity: 2, Flags: flagReadonly,
+			Group: "generic", Since: "1.0.0", Complexity: "O(N) with N being the number of keys in the database",
+			Summary: "Returns all key names that match a pattern.",
+			Handler: HandleKeys,
+		},
+		&Command{
+			Name: "scan", Arity: -2, Flags: flagReadonly,
+			Group: "generic", Since: "2.8.0", Complexity: "O(1) for every call. O(N) for a complete iteration, including enough command calls for the cursor to return back to 0. N is the number of elements inside the collection.",
+			Summary: "Iterates over the key names in the database.",
+			Handler: HandleScan,
+		},
+		&Command{
+			Name: "del", Arity: -2, Flags: flagWrite, FirstKey: 1, LastKey: -1, Step: 1,
+			Group: "generic", Since: "1.0.0", Complexity: "O(N) where N is the number of keys that will be removed.",
+			Summary: "Deletes one or more keys.",
+			Handler: HandleDel,
+		},
+		&Command{
+			Name: "unlink", Arity: -2, Flags: flagWrite, FirstKey: 1, LastKey: -1, Step: 1,
+			Group: "generic", Since: "4.0.0", Complexity: "O(1) for each key removed regardless of its size. Then the command does O(N) work in a different thread in order to reclaim memory, where N is the number of allocations the deleted objects where composed of.",
+			Summary: "Asynchronously deletes one or more keys.",
+			Handler: HandleUnlink,
+		},
+		&Command{
+			Name: "exists", Arity: -2, Flags: flagReadonly, FirstKey: 1, LastKey: -1, Step: 1,
+			Group: "generic", Since: "1.0.0", Complexity: "O(N) where N is the number of keys to check.",
+			Summary: "Determines whether one or more keys exist.",
+			Handler: HandleExists,
+		},
+		&Command{
+			Name: "rename", Arity: 3, Flags: flagWrite, FirstKey: 1, LastKey: 2, Step: 1,
+			Group: "generic", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Renames a key and overwrites the destination.",
+			Handler: HandleRename,
+		},
+		&Command{
+			Name: "renamenx", Arity: 3, Flags: flagWrite, FirstKey: 1, LastKey: 2, Step: 1,
+			Group: "generic", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Renames a key only when the target key name doesn't exist.",
+			Handler: HandleRenameNx,
+		},
+		&Command{
+			Name: "copy", Arity: -3, Flags: flagWrite, FirstKey: 1, LastKey: 2, Step: 1,
+			Group: "generic", Since: "6.2.0", Complexity: "O(N) worst case for collections, where N is the number of nested items. O(1) for string values.",
+			Summary: "Copies the value of a key to a new key.",
+			Handler: HandleCopy,
+		},
+		&Command{
+			Name: "touch", Arity: -2, Flags: flagReadonly, FirstKey: 1, LastKey: -1, Step: 1,
+			Group: "generic", Since: "3.2.1", Complexity: "O(N) where N is the number of keys that will be touched.",
+			Summary: "Returns the number of existing keys out of those specified after updating the time they were last accessed.",
+			Handler: HandleTouch,
+		},
+		&Command{
+			Name: "randomkey", Arity: 1, Flags: flagReadonly,
+			Group: "generic", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Returns a random key name from the database.",
+			Handler: HandleRandomKey,
+		},
+		&Command{
+			Name: "dbsize", Arity: 1, Flags: flagReadonly,
+			Group: "server", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Returns the number of keys in the database.",
+			Handler: HandleDbSize,
+		},
+		&Command{
+			Name: "flushall", Arity: -1, Flags: flagWrite,
+			Group: "server", Since: "1.0.0", Complexity: "O(N) where N is the total number of keys in all databases",
+			Summary: "Removes all keys from all databases.",
+			Handler: HandleFlushall,
+		},
+		&Command{
+			Name: "flushdb", Arity: -1, Flags: flagWrite,
+			Group: "server", Since: "1.0.0", Complexity: "O(N) where N is the number of keys in the selected database",
+			Summary: "Remove all keys from the current database.",
+			Handler: HandleFlushall,
+		},
+		&Command{
+			Name: "expire", Arity: -3, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "generic", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Sets the expiration time of a key in seconds.",
+			Handler: HandleExpire,
+		},
+		&Command{
+			Name: "pexpire", Arity: -3, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "generic", Since: "2.6.0", Complexity: "O(1)",
+			Summary: "Sets the expiration time of a key in milliseconds.",
+			Handler: HandlePexpire,
+		},
+		&Command{
+			Name: "expireat", Arity: -3, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "generic", Since: "1.2.0", Complexity: "O(1)",
+			Summary: "Sets the expiration time of a key to a Unix timestamp.",
+			Handler: HandleExpireAt,
+		},
+		&Command{
+			Name: "pexpireat", Arity: -3, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "generic", Since: "2.6.0", Complexity: "O(1)",
+			Summary: "Sets the expiration time of a key to a Unix milliseconds timestamp.",
+			Handler: HandlePexpireAt,
+		},
+		&Command{
+			Name: "ttl", Arity: 2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "generic", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Returns the expiration time in seconds of a key.",
+			Handler: HandleTTL,
+		},
+		&Command{
+			Name: "pttl", Arity: 2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "generic", Since: "2.6.0", Complexity: "O(1)",
+			Summary: "Returns the expiration time in milliseconds of a key.",
+			Handler: HandlePTTL,
+		},
+		&Command{
+			Name: "expiretime", Arity: 2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "generic", Since: "7.0.0", Complexity: "O(1)",
+			Summary: "Returns the expiration time of a key as a Unix timestamp.",
+			Handler: HandleExpireTime,
+		},
+		&Command{
+			Name: "pexpiretime", Arity: 2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "generic", Since: "7.0.0", Complexity: "O(1)",
+			Summary: "Returns the expiration time of a key as a Unix milliseconds timestamp.",
+			Handler: HandlePexpireTime,
+		},
+		&Command{
+			Name: "persist", Arity: 2, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "generic", Since: "2.2.0", Complexity: "O(1)",
+			Summary: "Removes the expiration time of a key.",
+			Handler: HandlePersist,
+		},
+		&Command{
+			Name: "lpush", Arity: -3, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "list", Since: "1.0.0", Complexity: "O(1) for each element added, so O(N) to add N elements when the command is called with multiple arguments.",
+			Summary: "Prepends one or more elements to a list. Creates the key if it doesn't exist.",
+			Handler: HandleLpush,
+		},
+		&Command{
+			Name: "rpush", Arity: -3, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "list", Since: "1.0.0", Complexity: "O(1) for each element added, so O(N) to add N elements when the command is called with multiple arguments.",
+			Summary: "Appends one or more elements to a list. Creates the key if it doesn't exist.",
+			Handler: HandleRpush,
+		},
+		&Command{
+			Name: "lpushx", Arity: -3, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "list", Since: "2.2.0", Complexity: "O(1) for each element added, so O(N) to add N elements when the command is called with multiple arguments.",
+			Summary: "Prepends one or more elements to a list only when the list exists.",
+			Handler: HandleLpushx,
+		},
+		&Command{
+			Name: "rpushx", Arity: -3, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "list", Since: "2.2.0", Complexity: "O(1) for each element added, so O(N) to add N elements when the command is called with multiple arguments.",
+			Summary: "Appends an element to a list only when the list exists.",
+			Handler: HandleRpushx,
+		},
+		&Command{
+			Name: "lpop", Arity: -2, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "list", Since: "1.0.0", Complexity: "O(N) where N is the number of elements returned",
+			Summary: "Returns the first elements in a list after removing it. Deletes the list if the last element was popped.",
+			Handler: HandleLpop,
+		},
+		&Command{
+			Name: "rpop", Arity: -2, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "list", Since: "1.0.0", Complexity: "O(N) where N is the number of elements returned",
+			Summary: "Returns and removes the last elements of a list. Deletes the list if the last element was popped.",
+			Handler: HandleRpop,
+		},
+		&Command{
+			Name: "lmpop", Arity: -4, Flags: flagWrite, KeysFunc: lmpopKeys,
+			Group: "list", Since: "7.0.0", Complexity: "O(N+M) where N is the number of provided keys and M is the number of elements returned.",
+			Summary: "Returns multiple elements from a list after removing them. Deletes the list if the last element was popped.",
+			Handler: HandleLmpop,
+		},
+		&Command{
+			Name: "blpop", Arity: -3, Flags: flagWrite | flagBlocking, FirstKey: 1, LastKey: -2, Step: 1,
+			Group: "list", Since: "2.0.0", Complexity: "O(N) where N is the number of provided keys.",
+			Summary: "Removes and returns the first element in a list. Blocks until an element is available otherwise. Deletes the list if the last element was popped.",
+			Handler: HandleBlpop,
+		},
+		&Command{
+			Name: "brpop", Arity: -3, Flags: flagWrite | flagBlocking, FirstKey: 1, LastKey: -2, Step: 1,
+			Group: "list", Since: "2.0.0", Complexity: "O(N) where N is the number of provided keys.",
+			Summary: "Removes and returns the last element in a list. Blocks until an element is available otherwise. Deletes the list if the last element was popped.",
+			Handler: HandleBrpop,
+		},
+		&Command{
+			Name: "blmpop", Arity: -5, Flags: flagWrite | flagBlocking, KeysFunc: blmpopKeys,
+			Group: "list", Since: "7.0.0", Complexity: "O(N+M) where N is the number of provided keys and M is the number of elements returned.",
+			Summary: "Pops the first element from one of multiple lists. Blocks until an element is available otherwise. Deletes the list if the last element was popped.",
+			Handler: HandleBlmpop,
+		},
+		&Command{
+			Name: "blmove", Arity: 6, Flags: flagWrite | flagBlocking, FirstKey: 1, LastKey: 2, Step: 1,
+			Group: "list", Since: "6.2.0", Complexity: "O(1)",
+			Summary: "Pops an element from a list, pushes it to another list and returns it. Blocks until an element is available otherwise. Deletes the list if the last element was moved.",
+			Handler: HandleBlmove,
+		},
+		&Command{
+			Name: "brpoplpush", Arity: 4, Flags: flagWrite | flagBlocking, FirstKey: 1, LastKey: 2, Step: 1,
+			Group: "list", Since: "2.2.0", Complexity: "O(1)",
+			Summary: "Pops an element from a list, pushes it to another list and returns it. Block until an element is available otherwise. Deletes the list if the last element was popped.",
+			Handler: HandleBrpoplpush,
+		},
+		&Command{
+			Name: "lrange", Arity: 4, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "list", Since: "1.0.0", Complexity: "O(S+N) where S is the distance of start offset from HEAD for small lists, from nearest end (HEAD or TAIL) for large lists; and N is the number of elements in the specified range.",
+			Summary: "Returns a range of elements from a list.",
+			Handler: HandleLrange,
+		},
+		&Command{
+			Name: "lindex", Arity: 3, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "list", Since: "1.0.0", Complexity: "O(N) where N is the number of elements to traverse to get to the element at index. This makes asking for the first or the last element of the list O(1).",
+			Summary: "Returns an element from a list by its index.",
+			Handler: HandleLindex,
+		},
+		&Command{
+			Name: "lset", Arity: 4, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "list", Since: "1.0.0", Complexity: "O(N) where N is the length of the list. Setting either the first or the last element of the list is O(1).",
+			Summary: "Sets the value of an element in a list by its index.",
+			Handler: HandleLset,
+		},
+		&Command{
+			Name: "linsert", Arity: 5, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "list", Since: "2.2.0", Complexity: "O(N) where N is the number of elements to traverse before seeing the value pivot. This means that inserting somewhere on the left end on the list (head) can be considered O(1) and inserting somewhere on the right end (tail) is O(N).",
+			Summary: "Inserts an element before or after another element in a list.",
+			Handler: HandleLinsert,
+		},
+		&Command{
+			Name: "lrem", Arity: 4, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "list", Since: "1.0.0", Complexity: "O(N+M) where N is the length of the list and M is the number of elements removed.",
+			Summary: "Removes elements from a list. Deletes the list if the last element was removed.",
+			Handler: HandleLrem,
+		},
+		&Command{
+			Name: "ltrim", Arity: 4, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "list", Since: "1.0.0", Complexity: "O(N) where N is the number of elements to be removed by the operation.",
+			Summary: "Removes elements from both ends a list. Deletes the list if all elements were trimmed.",
+			Handler: HandleLtrim,
+		},
+		&Command{
+			Name: "llen", Arity: 2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "list", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Returns the length of a list.",
+			Handler: HandleLlen,
+		},
+		&Command{
+			Name: "lpos", Arity: -3, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "list", Since: "6.0.6", Complexity: "O(N) where N is the number of elements in the list, for the average case. When searching for elements near the head or the tail of the list, or when the MAXLEN option is provided, the command may run in constant time.",
+			Summary: "Returns the index of matching elements in a list.",
+			Handler: HandleLpos,
+		},
+		&Command{
+			Name: "lmove", Arity: 5, Flags: flagWrite, FirstKey: 1, LastKey: 2, Step: 1,
+			Group: "list", Since: "6.2.0", Complexity: "O(1)",
+			Summary: "Returns an element after popping it from one list and pushing it to another. Deletes the list if the last element was moved.",
+			Handler: HandleLmove,
+		},
+		&Command{
+			Name: "rpoplpush", Arity: 3, Flags: flagWrite, FirstKey: 1, LastKey: 2, Step: 1,
+			Group: "list", Since: "1.2.0", Complexity: "O(1)",
+			Summary: "Returns the last element of a list after removing and pushing it to another list. Deletes the list if the last element was popped.",
+			Handler: HandleRpoplpush,
+		},
+		&Command{
+			Name: "hset", Arity: -4, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "2.0.0", Complexity: "O(1) for each field/value pair added, so O(N) to add N field/value pairs when the command is called with multiple field/value pairs.",
+			Summary: "Creates or modifies the value of a field in a hash.",
+			Handler: HandleHset,
+		},
+		&Command{
+			Name: "hsetnx", Arity: 4, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "2.0.0", Complexity: "O(1)",
+			Summary: "Sets the value of a field in a hash only when the field doesn't exist.",
+			Handler: HandleHsetnx,
+		},
+		&Command{
+			Name: "hmset", Arity: -4, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "2.0.0", Complexity: "O(N) where N is the number of fields being set.",
+			Summary: "Sets the values of multiple fields.",
+			Handler: HandleHmset,
+		},
+		&Command{
+			Name: "hget", Arity: 3, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "2.0.0", Complexity: "O(1)",
+			Summary: "Returns the value of a field in a hash.",
+			Handler: HandleHget,
+		},
+		&Command{
+			Name: "hmget", Arity: -3, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "2.0.0", Complexity: "O(N) where N is the number of fields being requested.",
+			Summary: "Returns the values of all fields in a hash.",
+			Handler: HandleHmget,
+		},
+		&Command{
+			Name: "hdel", Arity: -3, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "2.0.0", Complexity: "O(N) where N is the number of fields to be removed.",
+			Summary: "Deletes one or more fields and their values from a hash. Deletes the hash if no fields remain.",
+			Handler: HandleHdel,
+		},
+		&Command{
+			Name: "hlen", Arity: 2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "2.0.0", Complexity: "O(1)",
+			Summary: "Returns the number of fields in a hash.",
+			Handler: HandleHlen,
+		},
+		&Command{
+			Name: "hstrlen", Arity: 3, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "3.2.0", Complexity: "O(1)",
+			Summary: "Returns the length of the value of a field.",
+			Handler: HandleHstrlen,
+		},
+		&Command{
+			Name: "hexists", Arity: 3, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "2.0.0", Complexity: "O(1)",
+			Summary: "Determines whether a field exists in a hash.",
+			Handler: HandleHexists,
+		},
+		&Command{
+			Name: "hkeys", Arity: 2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "2.0.0", Complexity: "O(N) where N is the size of the hash.",
+			Summary: "Returns all fields in a hash.",
+			Handler: HandleHkeys,
+		},
+		&Command{
+			Name: "hvals", Arity: 2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "2.0.0", Complexity: "O(N) where N is the size of the hash.",
+			Summary: "Returns all values in a hash.",
+			Handler: HandleHvals,
+		},
+		&Command{
+			Name: "hgetall", Arity: 2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "2.0.0", Complexity: "O(N) where N is the size of the hash.",
+			Summary: "Returns all fields and values in a hash.",
+			Handler: HandleHgetall,
+		},
+		&Command{
+			Name: "hincrby", Arity: 4, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "2.0.0", Complexity: "O(1)",
+			Summary: "Increments the integer value of a field in a hash by a number. Uses 0 as initial value if the field doesn't exist.",
+			Handler: HandleHincrby,
+		},
+		&Command{
+			Name: "hincrbyfloat", Arity: 4, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "2.6.0", Complexity: "O(1)",
+			Summary: "Increments the floating point value of a field by a number. Uses 0 as initial value if the field doesn't exist.",
+			Handler: HandleHincrbyfloat,
+		},
+		&Command{
+			Name: "hscan", Arity: -3, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "2.8.0", Complexity: "O(1) for every call. O(N) for a complete iteration, including enough command calls for the cursor to return back to 0. N is the number of elements inside the collection.",
+			Summary: "Iterates over fields and values of a hash.",
+			Handler: HandleHscan,
+		},
+		&Command{
+			Name: "hrandfield", Arity: -2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "6.2.0", Complexity: "O(N) where N is the number of fields returned",
+			Summary: "Returns one or more random fields from a hash.",
+			Handler: HandleHrandfield,
+		},
+		&Command{
+			Name: "hexpire", Arity: -6, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "7.4.0", Complexity: "O(N) where N is the number of specified fields",
+			Summary: "Set expiry for hash field using relative time to expire (seconds)",
+			Handler: HandleHexpire,
+		},
+		&Command{
+			Name: "hpexpire", Arity: -6, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "7.4.0", Complexity: "O(N) where N is the number of specified fields",
+			Summary: "Set expiry for hash field using relative time to expire (milliseconds)",
+			Handler: HandleHpexpire,
+		},
+		&Command{
+			Name: "hexpireat", Arity: -6, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "7.4.0", Complexity: "O(N) where N is the number of specified fields",
+			Summary: "Set expiry for hash field using an absolute Unix timestamp (seconds)",
+			Handler: HandleHexpireAt,
+		},
+		&Command{
+			Name: "hpexpireat", Arity: -6, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "7.4.0", Complexity: "O(N) where N is the number of specified fields",
+			Summary: "Set expiry for hash field using an absolute Unix timestamp (milliseconds)",
+			Handler: HandleHpexpireAt,
+		},
+		&Command{
+			Name: "httl", Arity: -5, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "7.4.0", Complexity: "O(N) where N is the number of specified fields",
+			Summary: "Returns the TTL in seconds of a hash field.",
+			Handler: HandleHttl,
+		},
+		&Command{
+			Name: "hpttl", Arity: -5, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "7.4.0", Complexity: "O(N) where N is the number of specified fields",
+			Summary: "Returns the TTL in milliseconds of a hash field.",
+			Handler: HandleHpttl,
+		},
+		&Command{
+			Name: "hexpiretime", Arity: -5, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "7.4.0", Complexity: "O(N) where N is the number of specified fields",
+			Summary: "Returns the expiration time of a hash field as a Unix timestamp, in seconds.",
+			Handler: HandleHexpireTime,
+		},
+		&Command{
+			Name: "hpexpiretime", Arity: -5, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "7.4.0", Complexity: "O(N) where N is the number of specified fields",
+			Summary: "Returns the expiration time of a hash field as a Unix timestamp, in msec.",
+			Handler: HandleHpexpireTime,
+		},
+		&Command{
+			Name: "hpersist", Arity: -5, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "7.4.0", Complexity: "O(N) where N is the number of specified fields",
+			Summary: "Removes the expiration time for each specified field",
+			Handler: HandleHpersist,
+		},
+		&Command{
+			Name: "hgetex", Arity: -5, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "hash", Since: "8.0.0", Complexity: "O(N) where N is the number of specified fields",
+			Summary: "Get the value of one or more fields of a given hash key, and optionally set their expiration.",
+			Handler: HandleHgetex,
+		},
+		&Command{
+			Name: "sadd", Arity: -3, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "set", Since: "1.0.0", Complexity: "O(1) for each element added, so O(N) to add N elements when the command is called with multiple arguments.",
+			Summary: "Adds one or more members to a set. Creates the key if it doesn't exist.",
+			Handler: HandleSadd,
+		},
+		&Command{
+			Name: "srem", Arity: -3, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "set", Since: "1.0.0", Complexity: "O(N) where N is the number of members to be removed.",
+			Summary: "Removes one or more members from a set. Deletes the set if the last member was removed.",
+			Handler: HandleSrem,
+		},
+		&Command{
+			Name: "smembers", Arity: 2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "set", Since: "1.0.0", Complexity: "O(N) where N is the set cardinality.",
+			Summary: "Returns all members of a set.",
+			Handler: HandleSmembers,
+		},
+		&Command{
+			Name: "sismember", Arity: 3, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "set", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Determines whether a member belongs to a set.",
+			Handler: HandleSismember,
+		},
+		&Command{
+			Name: "smismember", Arity: -3, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "set", Since: "6.2.0", Complexity: "O(N) where N is the number of elements being checked for membership",
+			Summary: "Determines whether multiple members belong to a set.",
+			Handler: HandleSmismember,
+		},
+		&Command{
+			Name: "scard", Arity: 2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "set", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Returns the number of members in a set.",
+			Handler: HandleScard,
+		},
+		&Command{
+			Name: "spop", Arity: -2, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "set", Since: "1.0.0", Complexity: "Without the count argument O(1), otherwise O(N) where N is the value of the passed count.",
+			Summary: "Returns one or more random members from a set after removing them. Deletes the set if the last member was popped.",
+			Handler: HandleSpop,
+		},
+		&Command{
+			Name: "srandmember", Arity: -2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "set", Since: "1.0.0", Complexity: "Without the count argument O(1), otherwise O(N) where N is the absolute value of the passed count.",
+			Summary: "Get one or multiple random members from a set",
+			Handler: HandleSrandmember,
+		},
+		&Command{
+			Name: "smove", Arity: 4, Flags: flagWrite, FirstKey: 1, LastKey: 2, Step: 1,
+			Group: "set", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Moves a member from one set to another.",
+			Handler: HandleSmove,
+		},
+		&Command{
+			Name: "sinter", Arity: -2, Flags: flagReadonly, FirstKey: 1, LastKey: -1, Step: 1,
+			Group: "set", Since: "1.0.0", Complexity: "O(N*M) worst case where N is the cardinality of the smallest set and M is the number of sets.",
+			Summary: "Returns the intersect of multiple sets.",
+			Handler: HandleSinter,
+		},
+		&Command{
+			Name: "sinterstore", Arity: -3, Flags: flagWrite, FirstKey: 1, LastKey: -1, Step: 1,
+			Group: "set", Since: "1.0.0", Complexity: "O(N*M) worst case where N is the cardinality of the smallest set and M is the number of sets.",
+			Summary: "Stores the intersect of multiple sets in a key.",
+			Handler: HandleSinterstore,
+		},
+		&Command{
+			Name: "sintercard", Arity: -3, Flags: flagReadonly, KeysFunc: sintercardKeys,
+			Group: "set", Since: "7.0.0", Complexity: "O(N*M) worst case where N is the cardinality of the smallest set and M is the number of sets.",
+			Summary: "Returns the number of members of the intersect of multiple sets.",
+			Handler: HandleSintercard,
+		},
+		&Command{
+			Name: "sunion", Arity: -2, Flags: flagReadonly, FirstKey: 1, LastKey: -1, Step: 1,
+			Group: "set", Since: "1.0.0", Complexity: "O(N) where N is the total number of elements in all given sets.",
+			Summary: "Returns the union of multiple sets.",
+			Handler: HandleSunion,
+		},
+		&Command{
+			Name: "sunionstore", Arity: -3, Flags: flagWrite, FirstKey: 1, LastKey: -1, Step: 1,
+			Group: "set", Since: "1.0.0", Complexity: "O(N) where N is the total number of elements in all given sets.",
+			Summary: "Stores the union of multiple sets in a key.",
+			Handler: HandleSunionstore,
+		},
+		&Command{
+			Name: "sdiff", Arity: -2, Flags: flagReadonly, FirstKey: 1, LastKey: -1, Step: 1,
+			Group: "set", Since: "1.0.0", Complexity: "O(N) where N is the total number of elements in all given sets.",
+			Summary: "Returns the difference of multiple sets.",
+			Handler: HandleSdiff,
+		},
+		&Command{
+			Name: "sdiffstore", Arity: -3, Flags: flagWrite, FirstKey: 1, LastKey: -1, Step: 1,
+			Group: "set", Since: "1.0.0", Complexity: "O(N) where N is the total number of elements in all given sets.",
+			Summary: "Stores the difference of multiple sets in a key.",
+			Handler: HandleSdiffstore,
+		},
+		&Command{
+			Name: "sscan", Arity: -3, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "set", Since: "2.8.0", Complexity: "O(1) for every call. O(N) for a complete iteration, including enough command calls for the cursor to return back to 0. N is the number of elements inside the collection.",
+			Summary: "Iterates over members of a set.",
+			Handler: HandleSscan,
+		},
+		&Command{
+			Name: "zadd", Arity: -4, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "1.2.0", Complexity: "O(log(N)) for each item added, where N is the number of elements in the sorted set.",
+			Summary: "Adds one or more members to a sorted set, or updates their scores. Creates the key if it doesn't exist.",
+			Handler: HandleZadd,
+		},
+		&Command{
+			Name: "zincrby", Arity: 4, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "1.2.0", Complexity: "O(log(N)) where N is the number of elements in the sorted set.",
+			Summary: "Increments the score of a member in a sorted set.",
+			Handler: HandleZincrby,
+		},
+		&Command{
+			Name: "zrem", Arity: -3, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "1.2.0", Complexity: "O(M*log(N)) with N being the number of elements in the sorted set and M the number of elements to be removed.",
+			Summary: "Removes one or more members from a sorted set. Deletes the sorted set if all members were removed.",
+			Handler: HandleZrem,
+		},
+		&Command{
+			Name: "zremrangebyrank", Arity: 4, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "2.0.0", Complexity: "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements removed by the operation.",
+			Summary: "Removes members in a sorted set within a range of indexes. Deletes the sorted set if all members were removed.",
+			Handler: HandleZremrangebyrank,
+		},
+		&Command{
+			Name: "zremrangebyscore", Arity: 4, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "1.2.0", Complexity: "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements removed by the operation.",
+			Summary: "Removes members in a sorted set within a range of scores. Deletes the sorted set if all members were removed.",
+			Handler: HandleZremrangebyscore,
+		},
+		&Command{
+			Name: "zremrangebylex", Arity: 4, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "2.8.9", Complexity: "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements removed by the operation.",
+			Summary: "Removes members in a sorted set within a lexicographical range. Deletes the sorted set if all members were removed.",
+			Handler: HandleZremrangebylex,
+		},
+		&Command{
+			Name: "zcard", Arity: 2, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "1.2.0", Complexity: "O(1)",
+			Summary: "Returns the number of members in a sorted set.",
+			Handler: HandleZcard,
+		},
+		&Command{
+			Name: "zcount", Arity: 4, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "2.0.0", Complexity: "O(log(N)) with N being the number of elements in the sorted set.",
+			Summary: "Returns the count of members in a sorted set that have scores within a range.",
+			Handler: HandleZcount,
+		},
+		&Command{
+			Name: "zlexcount", Arity: 4, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "2.8.9", Complexity: "O(log(N)) with N being the number of elements in the sorted set.",
+			Summary: "Returns the number of members in a sorted set within a lexicographical range.",
+			Handler: HandleZlexcount,
+		},
+		&Command{
+			Name: "zscore", Arity: 3, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "1.2.0", Complexity: "O(1)",
+			Summary: "Returns the score of a member in a sorted set.",
+			Handler: HandleZscore,
+		},
+		&Command{
+			Name: "zmscore", Arity: -3, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "6.2.0", Complexity: "O(N) where N is the number of members being requested.",
+			Summary: "Returns the score of one or more members in a sorted set.",
+			Handler: HandleZmscore,
+		},
+		&Command{
+			Name: "zrank", Arity: -3, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "2.0.0", Complexity: "O(log(N))",
+			Summary: "Returns the index of a member in a sorted set ordered by ascending scores.",
+			Handler: HandleZrank,
+		},
+		&Command{
+			Name: "zrevrank", Arity: -3, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "2.0.0", Complexity: "O(log(N))",
+			Summary: "Returns the index of a member in a sorted set ordered by descending scores.",
+			Handler: HandleZrevrank,
+		},
+		&Command{
+			Name: "zrange", Arity: -4, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "1.2.0", Complexity: "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements returned.",
+			Summary: "Returns members in a sorted set within a range of indexes.",
+			Handler: HandleZrange,
+		},
+		&Command{
+			Name: "zrangestore", Arity: -5, Flags: flagWrite, FirstKey: 1, LastKey: 2, Step: 1,
+			Group: "sorted-set", Since: "6.2.0", Complexity: "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements stored into the destination key.",
+			Summary: "Stores a range of members from sorted set in a key.",
+			Handler: HandleZrangestore,
+		},
+		&Command{
+			Name: "zrevrange", Arity: -4, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "1.2.0", Complexity: "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements returned.",
+			Summary: "Returns members in a sorted set within a range of indexes in reverse order.",
+			Handler: HandleZrevrange,
+		},
+		&Command{
+			Name: "zrangebyscore", Arity: -4, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "1.0.5", Complexity: "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements being returned.",
+			Summary: "Returns members in a sorted set within a range of scores.",
+			Handler: HandleZrangebyscore,
+		},
+		&Command{
+			Name: "zrevrangebyscore", Arity: -4, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "2.2.0", Complexity: "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements being returned.",
+			Summary: "Returns members in a sorted set within a range of scores in reverse order.",
+			Handler: HandleZrevrangebyscore,
+		},
+		&Command{
+			Name: "zrangebylex", Arity: -4, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "2.8.9", Complexity: "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements being returned.",
+			Summary: "Returns members in a sorted set within a lexicographical range.",
+			Handler: HandleZrangebylex,
+		},
+		&Command{
+			Name: "zrevrangebylex", Arity: -4, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "2.8.9", Complexity: "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements being returned.",
+			Summary: "Returns members in a sorted set within a lexicographical range in reverse order.",
+			Handler: HandleZrevrangebylex,
+		},
+		&Command{
+			Name: "zpopmin", Arity: -2, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "5.0.0", Complexity: "O(log(N)*M) with N being the number of elements in the sorted set, and M being the number of elements popped.",
+			Summary: "Returns the lowest-scoring members from a sorted set after removing them. Deletes the sorted set if the last member was popped.",
+			Handler: HandleZpopmin,
+		},
+		&Command{
+			Name: "zpopmax", Arity: -2, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "5.0.0", Complexity: "O(log(N)*M) with N being the number of elements in the sorted set, and M being the number of elements popped.",
+			Summary: "Returns the highest-scoring members from a sorted set after removing them. Deletes the sorted set if the last member was popped.",
+			Handler: HandleZpopmax,
+		},
+		&Command{
+			Name: "bzpopmin", Arity: -3, Flags: flagWrite | flagBlocking, FirstKey: 1, LastKey: -2, Step: 1,
+			Group: "sorted-set", Since: "5.0.0", Complexity: "O(log(N)) with N being the number of elements in the sorted set.",
+			Summary: "Removes and returns the member with the lowest score from one or more sorted sets. Blocks until a member is available otherwise. Deletes the sorted set if the last element was popped.",
+			Handler: HandleBzpopmin,
+		},
+		&Command{
+			Name: "bzpopmax", Arity: -3, Flags: flagWrite | flagBlocking, FirstKey: 1, LastKey: -2, Step: 1,
+			Group: "sorted-set", Since: "5.0.0", Complexity: "O(log(N)) with N being the number of elements in the sorted set.",
+			Summary: "Removes and returns the member with the highest score from one or more sorted sets. Blocks until a member is available otherwise. Deletes the sorted set if the last element was popped.",
+			Handler: HandleBzpopmax,
+		},
+		&Command{
+			Name: "zunion", Arity: -3, Flags: flagReadonly, KeysFunc: zsetAlgebraKeys,
+			Group: "sorted-set", Since: "6.2.0", Complexity: "O(N)+O(M*log(M)) with N being the sum of the sizes of the input sorted sets, and M being the number of elements in the resulting sorted set.",
+			Summary: "Returns the union of multiple sorted sets.",
+			Handler: HandleZunion,
+		},
+		&Command{
+			Name: "zunionstore", Arity: -4, Flags: flagWrite, KeysFunc: zsetAlgebraStoreKeys,
+			Group: "sorted-set", Since: "2.0.0", Complexity: "O(N)+O(M log(M)) with N being the sum of the sizes of the input sorted sets, and M being the number of elements in the resulting sorted set.",
+			Summary: "Stores the union of multiple sorted sets in a key.",
+			Handler: HandleZunionstore,
+		},
+		&Command{
+			Name: "zinter", Arity: -3, Flags: flagReadonly, KeysFunc: zsetAlgebraKeys,
+			Group: "sorted-set", Since: "6.2.0", Complexity: "O(N*K)+O(M*log(M)) worst case with N being the smallest input sorted set, K being the number of input sorted sets and M being the number of elements in the resulting sorted set.",
+			Summary: "Returns the intersect of multiple sorted sets.",
+			Handler: HandleZinter,
+		},
+		&Command{
+			Name: "zinterstore", Arity: -4, Flags: flagWrite, KeysFunc: zsetAlgebraStoreKeys,
+			Group: "sorted-set", Since: "2.0.0", Complexity: "O(N*K)+O(M*log(M)) worst case with N being the smallest input sorted set, K being the number of input sorted sets and M being the number of elements in the resulting sorted set.",
+			Summary: "Stores the intersect of multiple sorted sets in a key.",
+			Handler: HandleZinterstore,
+		},
+		&Command{
+			Name: "zdiff", Arity: -3, Flags: flagReadonly, KeysFunc: zsetAlgebraKeys,
+			Group: "sorted-set", Since: "6.2.0", Complexity: "O(L + (N-K)log(N)) worst case where L is the total number of elements in all the sets, N is the size of the first set, and K is the size of the result set.",
+			Summary: "Returns the difference between multiple sorted sets.",
+			Handler: HandleZdiff,
+		},
+		&Command{
+			Name: "zdiffstore", Arity: -4, Flags: flagWrite, KeysFunc: zsetAlgebraStoreKeys,
+			Group: "sorted-set", Since: "6.2.0", Complexity: "O(L + (N-K)log(N)) worst case where L is the total number of elements in all the sets, N is the size of the first set, and K is the size of the result set.",
+			Summary: "Stores the difference of multiple sorted sets in a key.",
+			Handler: HandleZdiffstore,
+		},
+		&Command{
+			Name: "zscan", Arity: -3, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "sorted-set", Since: "2.8.0", Complexity: "O(1) for every call. O(N) for a complete iteration, including enough command calls for the cursor to return back to 0. N is the number of elements inside the collection.",
+			Summary: "Iterates over members and scores of a sorted set.",
+			Handler: HandleZscan,
+		},
+		&Command{
+			Name: "xadd", Arity: -5, Flags: flagWrite, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "stream", Since: "5.0.0", Complexity: "O(1) when adding a new entry",
+			Summary: "Appends a new message to a stream. Creates the key if it doesn't exist.",
+			Handler: HandleXadd,
+		},
+		&Command{
+			Name: "xrange", Arity: -4, Flags: flagReadonly, FirstKey: 1, LastKey: 1, Step: 1,
+			Group: "stream", Since: "5.0.0", Complexity: "O(N) with N being the number of elements being returned",
+			Summary: "Returns the messages from a stream within a range of IDs.",
+			Handler: HandleXrange,
+		},
+		&Command{
+			Name: "xread", Arity: -4, Flags: flagReadonly | flagBlocking, KeysFunc: xreadKeys,
+			Group: "stream", Since: "5.0.0", Complexity: "O(N) with N being the number of elements being returned",
+			Summary: "Returns messages from multiple streams with IDs greater than the ones requested. Blocks until a message is available otherwise.",
+			Handler: HandleXread,
+		},
+		&Command{
+			Name: "multi", Arity: 1, Flags: flagNoScript | flagLoading | flagNoMulti,
+			Group: "transactions", Since: "1.2.0", Complexity: "O(1)",
+			Summary: "Starts a transaction.",
+			Handler: HandleMulti,
+		},
+		&Command{
+			Name: "exec", Arity: 1, Flags: flagNoScript | flagLoading,
+			Group: "transactions", Since: "1.2.0", Complexity: "Depends on commands in the transaction",
+			Summary: "Executes all commands in a transaction.",
+			Handler: HandleExec,
+		},
+		&Command{
+			Name: "discard", Arity: 1, Flags: flagNoScript | flagLoading,
+			Group: "transactions", Since: "2.0.0", Complexity: "O(N), when N is the number of queued commands",
+			Summary: "Discards a transaction.",
+			Handler: HandleDiscard,
+		},
+		&Command{
+			Name: "watch", Arity: -2, Flags: flagNoScript | flagLoading | flagNoMulti, FirstKey: 1, LastKey: -1, Step: 1,
+			Group: "transactions", Since: "2.2.0", Complexity: "O(1) for every key.",
+			Summary: "Monitors changes to keys to determine the execution of a transaction.",
+			Handler: HandleWatch,
+		},
+		&Command{
+			Name: "unwatch", Arity: 1, Flags: flagNoScript | flagLoading,
+			Group: "transactions", Since: "2.2.0", Complexity: "O(1)",
+			Summary: "Forgets about watched keys of a transaction.",
+			Handler: HandleUnwatch,
+		},
+		&Command{
+			Name: "config", Arity: -2,
+			Group: "server", Since: "2.0.0", Complexity: "Depends on subcommand.",
+			Summary: "A container for server configuration commands.",
+			Subcommands: []*Command{
+				{
+					Name: "get", Arity: -3, Flags: flagAdmin | flagNoScript | flagLoading,
+					Group: "server", Since: "2.0.0", Complexity: "O(N) when N is the number of configuration parameters provided",
+					Summary: "Returns the effective values of configuration parameters.",
+					Handler: HandleConfigGet,
+				},
+				{
+					Name: "set", Arity: -4, Flags: flagAdmin | flagNoScript | flagLoading,
+					Group: "server", Since: "2.0.0", Complexity: "O(N) when N is the number of configuration parameters provided",
+					Summary: "Sets configuration parameters in-flight.",
+					Handler: HandleConfigSet,
+				},
+			},
+		},
+		&Command{
+			Name: "command", Arity: -1, Flags: flagLoading,
+			Group: "server", Since: "2.8.13", Complexity: "O(N) where N is the total number of Redis commands",
+			Summary: "Returns detailed information about all commands.",
+			Handler: HandleCommand,
+			Subcommands: []*Command{
+				{
+					Name: "count", Arity: 2, Flags: flagLoading,
+					Group: "server", Since: "2.8.13", Complexity: "O(1)",
+					Summary: "Returns a count of commands.",
+					Handler: HandleCommandCount,
+				},
+				{
+					Name: "info", Arity: -2, Flags: flagLoading,
+					Group: "server", Since: "2.8.13", Complexity: "O(N) where N is the number of commands to look up",
+					Summary: "Returns information about one, multiple or all commands.",
+					Handler: HandleCommandInfo,
+				},
+				{
+					Name: "docs", Arity: -2, Flags: flagLoading,
+					Group: "server", Since: "7.0.0", Complexity: "O(N) where N is the number of commands to look up",
+					Summary: "Returns documentary information about one, multiple or all commands.",
+					Handler: HandleCommandDocs,
+				},
+				{
+					Name: "getkeys", Arity: -3, Flags: flagLoading,
+					Group: "server", Since: "2.8.13", Complexity: "O(N) where N is the number of arguments to the command",
+					Summary: "Extracts the key names from an arbitrary command.",
+					Handler: HandleCommandGetKeys,
+				},
+				{
+					Name: "list", Arity: -2, Flags: flagLoading,
+					Group: "server", Since: "7.0.0", Complexity: "O(N) where N is the total number of Redis commands",
+					Summary: "Returns a list of command names.",
+					Handler: HandleCommandList,
+				},
+			},
+		},
+		&Command{
+			Name: "info", Arity: -1, Flags: flagLoading,
+			Group: "server", Since: "1.0.0", Complexity: "O(1)",
+			Summary: "Returns information and statistics about the server.",
+			Handler: HandleInfo,
+		},
+		&Command{
+			Name: "replconf", Arity: -1, Flags: flagAdmin | flagNoScript | flagLoading,
+			Group: "server", Since: "3.0.0", Complexity: "O(1)",
+			Summary: "An internal command for configuring the replication stream.",
+			Handler: HandleReplConf,
+		},
+		&Command{
+			Name: "psync", Arity: -3, Flags: flagAdmin | flagNoScript | flagNoMulti,
+			Group: "server", Since: "2.8.0", Complexity: "O(1)",
+			Summary: "An internal command used in replication.",
+			Handler: HandlePsync,
+		},
+		&Command{
+			Name: "wait", Arity: 3, Flags: flagNoScript,
+			Group: "generic", Since: "3.0.0", Complexity: "O(1)",
+			Summary: "Blocks until the asynchronous replication of all preceding write commands sent by the connection is completed.",
+			Handler: HandleWait,
+		},
+	)
+}
+
+func registerCommands(cmds ...*Command) {
+	for _, cmd := range cmds {
+		for _, sub := range cmd.Subcommands {
+			sub.parent = cmd
+		}
+		commandTable[cmd.Name] = cmd
+	}
+}
+
+func lookupCommand(name string) (*Command, bool) {
+	cmd, ok := commandTable[strings.ToLower(name)]
+	return cmd, ok
+}
+
+// sortedCommands returns the top-level commands ordered by name.
+func sortedCommands() []*Command {
+	cmds := make([]*Command, 0, len(commandTable))
+	for _, cmd := range commandTable {
+		cmds = append(cmds, cmd)
+	}
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i].Name < cmds[j].Name })
+	return cmds
+}
+
+func (c *Command) subcommand(name string) (*Command, bool) {
+	name = strings.ToLower(name)
+	for _, sub := range c.Subcommands {
+		if sub.Name == name {
+			return sub, true
+		}
+	}
+	return nil, false
+}
+
+// FullName is the name used in replies and errors, "container|sub" for
+// subcommands.
+func (c *Command) FullName() string {
+	if c.parent != nil {
+		return c.parent.Name + "|" + c.Name
+	}
+	return c.Name
+}
+
+func (c *Command) has(flag commandFlag) bool {
+	return c.Flags&flag != 0
+}
+
+// checkArity reports whether argc (including the command name) satisfies
+// the command's arity.
+func (c *Command) checkArity(argc int) bool {
+	if c.Arity >= 0 {
+		return argc == c.Arity
+	}
+	return argc >= -c.Arity
+}
+
+// keyPositions returns the indexes of the key arguments in args, which must
+// already satisfy the command's arity.
+func (c *Command) keyPositions(args []string) []int {
+	if c.KeysFunc != nil {
+		return c.KeysFunc(args)
+	}
+	if c.FirstKey == 0 {
+		return nil
+	}
+
+	last := c.LastKey
+	if last < 0 {
+		last = len(args) + last
+	}
+	positions := make([]int, 0, 1)
+	for i := c.FirstKey; i <= last && i < len(args); i += c.Step {
+		positions = append(positions, i)
+	}
+	return positions
+}
@@ -1,24 +1,135 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
-	"net"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser"
 )
 
-func HandleConfig(conn net.Conn, args []string) {
-	if len(args) != 3 || args[1] != "GET" {
-		conn.Write([]byte("-ERR syntax error\r\n"))
-		return
+// configSetters validate and apply the parameters that can be changed at
+// runtime with CONFIG SET (or at startup from the command line). The value
+// stored for CONFIG GET is the one they return.
+var configSetters = map[string]func(value string) (string, error){
+	"proto-max-bulk-len":        setProtoMaxBulkLen,
+	"requirepass":               func(value string) (string, error) { return value, nil },
+	"hash-max-listpack-entries": intConfig(core.SetHashMaxListpackEntries),
+	"hash-max-listpack-value":   intConfig(core.SetHashMaxListpackValue),
+	"set-max-intset-entries":    intConfig(core.SetSetMaxIntsetEntries),
+	"hll-sparse-max-bytes":      intConfig(core.SetHllSparseMaxBytes),
+}
+
+func HandleConfigGet(c *Client, args []string) error {
+	var keys, values []string
+	for _, key := range args[2:] {
+		value, exists := GetConfig(key)
+		if !exists {
+			continue
+		}
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+
+	// a map of parameter -> value (a flat array in RESP2)
+	c.Reply.MapLen(len(keys))
+	for i := range keys {
+		c.Reply.Bulk(keys[i])
+		c.Reply.Bulk(values[i])
+	}
+	return nil
+}
+
+func HandleConfigSet(c *Client, args []string) error {
+	if len(args)%2 != 0 {
+		return errors.New("ERR wrong number of arguments for 'config|set' command")
+	}
+
+	// validate everything before applying anything
+	for i := 2; i < len(args); i += 2 {
+		if _, ok := configSetters[strings.ToLower(args[i])]; !ok {
+			return fmt.Errorf("ERR Unknown option or number of arguments for CONFIG SET - '%s'", args[i])
+		}
+	}
+	for i := 2; i < len(args); i += 2 {
+		if err := ApplyConfig(args[i], args[i+1]); err != nil {
+			return err
+		}
+	}
+
+	c.Reply.OK()
+	return nil
+}
+
+// ApplyConfig sets a runtime-configurable parameter.
+func ApplyConfig(key, value string) error {
+	key = strings.ToLower(key)
+	setter, ok := configSetters[key]
+	if !ok {
+		return fmt.Errorf("ERR Unknown option or number of arguments for CONFIG SET - '%s'", key)
+	}
+
+	normalized, err := setter(value)
+	if err != nil {
+		return fmt.Errorf("ERR CONFIG SET failed (possibly related to argument '%s') - %s", key, err.Error())
+	}
+	SetConfig(key, normalized)
+	return nil
+}
+
+func setProtoMaxBulkLen(value string) (string, error) {
+	n, err := parseMemory(value)
+	if err != nil {
+		return "", err
+	}
+	if n < 1024*1024 {
+		return "", errors.New("argument must be between 1048576 and 9223372036854775807 inclusive")
+	}
+	parser.SetMaxBulkLen(n)
+	return strconv.FormatInt(n, 10), nil
+}
+
+// intConfig makes the setter of a non-negative integer parameter.
+func intConfig(set func(n int64)) func(value string) (string, error) {
+	return func(value string) (string, error) {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", errors.New("argument couldn't be parsed into an integer")
+		}
+		if n < 0 {
+			return "", errors.New("argument must be between 0 and 9223372036854775807 inclusive")
+		}
+		set(n)
+		return strconv.FormatInt(n, 10), nil
 	}
+}
 
-	key := args[2]
-	value, exists := GetConfig(key)
-	if !exists {
-		conn.Write([]byte("$-1\r\n"))
-		return
+// parseMemory parses a memory size like "512mb", "1gb" or "1048576".
+func parseMemory(value string) (int64, error) {
+	units := []struct {
+		suffix string
+		mul    int64
+	}{
+		{"kb", 1024}, {"mb", 1024 * 1024}, {"gb", 1024 * 1024 * 1024},
+		{"k", 1000}, {"m", 1000 * 1000}, {"g", 1000 * 1000 * 1000},
+		{"b", 1},
 	}
 
-	// RESP Array: *2\r\n$len(key)\r\nkey\r\n$len(value)\r\nvalue\r\n
-	resp := fmt.Sprintf("*2\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n", len(key), key, len(value), value)
-	conn.Write([]byte(resp))
+	lower := strings.ToLower(value)
+	mul := int64(1)
+	for _, u := range units {
+		if strings.HasSuffix(lower, u.suffix) {
+			lower = strings.TrimSuffix(lower, u.suffix)
+			mul = u.mul
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(lower, 10, 64)
+	if err != nil || n < 0 {
+		return 0, errors.New("argument must be a memory value")
+	}
+	return n * mul, nil
 }
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+)
+
+// HandleCopy copies the value of a key, TTL included, to another key:
+// COPY source destination [DB destination-db] [REPLACE]. There's only
+// database 0.
+func HandleCopy(c *Client, args []string) error {
+	src, dst := args[1], args[2]
+
+	replace := false
+	for i := 3; i < len(args); i++ {
+		switch {
+		case strings.ToUpper(args[i]) == "REPLACE":
+			replace = true
+		case strings.ToUpper(args[i]) == "DB" && i+1 < len(args):
+			i++
+			if args[i] != "0" {
+				return errors.New("ERR DB index is out of range")
+			}
+		default:
+			return errSyntax
+		}
+	}
+
+	if src == dst {
+		return errors.New("ERR source and destination objects are the same")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	entry, exists := lookupKeyWrite(src)
+	if !exists {
+		preventPropagation(c)
+		c.Reply.Integer(0)
+		return nil
+	}
+	if _, exists := lookupKeyWrite(dst); exists && !replace {
+		preventPropagation(c)
+		c.Reply.Integer(0)
+		return nil
+	}
+
+	entry.Data = dupValue(entry.Data)
+	setKey(dst, entry)
+	c.Reply.Integer(1)
+	return nil
+}
+
+// dupValue returns a copy of a value that shares nothing mutable with it.
+func dupValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case core.Stream:
+		entries := make([]core.StreamEntry, len(v.Entries))
+		for i, e := range v.Entries {
+			fields := make(map[string]string, len(e.Fields))
+			for field, val := range e.Fields {
+				fields[field] = val
+			}
+			entries[i] = core.StreamEntry{ID: e.ID, Fields: fields}
+		}
+		return core.Stream{Entries: entries}
+	case *core.Quicklist:
+		return v.Dup()
+	case *core.Hash:
+		return v.Dup()
+	case *core.Set:
+		return v.Dup()
+	case *core.SortedSet:
+		return v.Dup()
+	}
+	return value // strings are immutable
+}
@@ -0,0 +1,11 @@
+package commands
+
+// HandleDbSize replies with the number of keys, including expired ones the
+// active expire cycle hasn't reclaimed yet (as Redis does).
+func HandleDbSize(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	c.Reply.Integer(int64(store.Len()))
+	return nil
+}
@@ -0,0 +1,35 @@
+package commands
+
+func HandleDel(c *Client, args []string) error {
+	return delGeneric(c, args[1:])
+}
+
+// HandleUnlink is DEL. Redis frees big values in a background thread so
+// that deleting them doesn't take O(N) on the command path, but here
+// deleting a key only drops a reference, and the garbage collector already
+// reclaims the value concurrently.
+func HandleUnlink(c *Client, args []string) error {
+	return delGeneric(c, args[1:])
+}
+
+func delGeneric(c *Client, keys []string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	deleted := 0
+	for _, key := range keys {
+		// an expired key doesn't count, but is still removed: on a replica
+		// this DEL is how the master tells us it has expired
+		expired := expireIfNeeded(key)
+		if !deleteKey(key) || expired {
+			continue
+		}
+		deleted++
+	}
+
+	if deleted == 0 {
+		preventPropagation(c)
+	}
+	c.Reply.Integer(int64(deleted))
+	return nil
+}
@@ -1,24 +1,29 @@
 package commands
 
 import (
-	"net"
+	"errors"
 
 	models "github.com/codecrafters-io/redis-starter-go/internal/models/core"
 )
 
-func HandleDiscard(conn net.Conn) {
+func HandleDiscard(c *Client, args []string) error {
 	models.ClientMu.Lock()
-	defer models.ClientMu.Unlock()
-
-	state, exists := models.ClientStates[conn]
-	if !exists || !state.InTransaction {
-		conn.Write([]byte("-ERR DISCARD without MULTI\r\n"))
-		return
+	if !c.InTransaction {
+		models.ClientMu.Unlock()
+		return errors.New("ERR DISCARD without MULTI")
 	}
 
 	// reset transaction state and clear the command queue
-	state.InTransaction = false
-	state.CommandQueue = make([][]string, 0)
+	c.InTransaction = false
+	c.CommandQueue = make([][]string, 0)
+	c.DirtyExec = false
+	models.ClientMu.Unlock()
+
+	mu.Lock()
+	unwatchAllKeys(c)
+	c.DirtyCAS = false
+	mu.Unlock()
 
-	conn.Write([]byte("+OK\r\n"))
+	c.Reply.OK()
+	return nil
 }
@@ -2,15 +2,10 @@ package commands
 
 import (
 	"fmt"
-	"net"
 )
 
-func HandleEcho(conn net.Conn, args []string) {
-	if len(args) > 1 {
-		response := args[1]
-		conn.Write([]byte(fmt.Sprintf("$%d\r\n%s\r\n", len(response), response)))
-	} else {
-		conn.Write([]byte("$0\r\n\r\n"))
-	}
+func HandleEcho(c *Client, args []string) error {
+	c.Reply.Bulk(args[1])
 	fmt.Println("Processed ECHO command:", args)
+	return nil
 }
@@ -0,0 +1,11 @@
+package commands
+
+import "errors"
+
+// Errors shared by several handlers. Handler errors are written to the
+// client verbatim, so each one starts with its Redis error code.
+var (
+	errSyntax     = errors.New("ERR syntax error")
+	errWrongType  = errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
+	errNotInteger = errors.New("ERR value is not an integer or out of range")
+)
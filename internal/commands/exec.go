@@ -1,117 +1,81 @@
 package commands
 
 import (
-	"fmt"
-	"net"
-	"strconv"
-	"strings"
+	"errors"
+	"sync"
 
-	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
 	models "github.com/codecrafters-io/redis-starter-go/internal/models/core"
 	"github.com/codecrafters-io/redis-starter-go/internal/replication"
 )
 
-func HandleExec(conn net.Conn) {
+var (
+	// execMu makes transactions atomic. Every command runs holding it for
+	// reading, and EXEC holds it for writing while it runs the queued
+	// commands, so no other client can see or touch the keyspace half-way
+	// through.
+	execMu sync.RWMutex
+	// execPropagate collects what the commands run by EXEC replicate. It
+	// is nil unless EXEC is running, and guarded by execMu.
+	execPropagate [][]string
+)
+
+// HandleExec runs the commands queued since MULTI, replying with an array
+// of their replies. A command failing at runtime only puts its error in
+// that array; the others still run, as in Redis. Replicas get the writes
+// of the transaction wrapped in MULTI/EXEC, so they apply them as one unit
+// too.
+func HandleExec(c *Client, args []string) error {
 	models.ClientMu.Lock()
-	state, exists := models.ClientStates[conn]
-	if !exists || !state.InTransaction {
+	if !c.InTransaction {
 		models.ClientMu.Unlock()
-		conn.Write([]byte("-ERR EXEC without MULTI\r\n"))
-		return
+		return errors.New("ERR EXEC without MULTI")
 	}
 
-	state.InTransaction = false
-	queuedCommands := state.CommandQueue
-	state.CommandQueue = make([][]string, 0)
+	c.InTransaction = false
+	queuedCommands := c.CommandQueue
+	c.CommandQueue = make([][]string, 0)
+	dirtyExec := c.DirtyExec
+	c.DirtyExec = false
 	models.ClientMu.Unlock()
 
-	responses := make([]string, 0, len(queuedCommands))
-	for _, args := range queuedCommands {
-		response := executeCommand(conn, args)
-		responses = append(responses, response)
-	}
-
-	// Build and send the RESP array response
-	result := fmt.Sprintf("*%d\r\n", len(responses))
-	for _, r := range responses {
-		result += r
-	}
-	conn.Write([]byte(result))
-}
-
-func executeCommand(conn net.Conn, args []string) string {
-	cmd := strings.ToUpper(args[0])
-	switch cmd {
-	case "SET":
-		return handleSetInTransaction(conn, args)
-	case "INCR":
-		return handleIncrInTransaction(conn, args)
-	case "GET":
-		return handleGetInTransaction(conn, args)
-	default:
-		return "+OK\r\n"
+	execMu.Lock()
+	defer execMu.Unlock()
+
+	// the transaction is off if a watched key was modified, or has expired
+	mu.Lock()
+	aborted := c.DirtyCAS || watchedKeyExpired(c)
+	unwatchAllKeys(c)
+	c.DirtyCAS = false
+	mu.Unlock()
+	if dirtyExec {
+		return errors.New("EXECABORT Transaction discarded because of previous errors.")
 	}
-}
-
-func handleSetInTransaction(conn net.Conn, args []string) string {
-	if len(args) < 3 {
-		return "-ERR wrong number of arguments for 'SET' command\r\n"
+	if aborted {
+		c.Reply.NullArray()
+		return nil
 	}
 
-	key := args[1]
-	value := args[2]
-	var ttl int64 = 0
-
-	if len(args) >= 5 && strings.ToUpper(args[3]) == "PX" {
-		parsedTTL, err := strconv.ParseInt(args[4], 10, 64)
-		if err != nil || parsedTTL <= 0 {
-			return "-ERR PX must be a positive integer\r\n"
+	// each queued command writes its reply as the next array element
+	c.InExec = true
+	execPropagate = [][]string{}
+	c.Reply.ArrayLen(len(queuedCommands))
+	for _, args := range queuedCommands {
+		// the queue only keeps the arguments, which passed these checks
+		// already when queued
+		cmd, err := resolveCommand(args)
+		if err != nil {
+			c.Reply.Error(err.Error())
+			continue
 		}
-		ttl = parsedTTL
+		call(c, cmd, args, c.Master)
 	}
+	c.InExec = false
 
-	SetKey(key, value, ttl)
-	replication.PropagateCommand("SET", args[1:])
-	return "+OK\r\n"
-}
-
-func handleIncrInTransaction(conn net.Conn, args []string) string {
-	if len(args) < 2 {
-		return "-ERR wrong number of arguments for 'INCR' command\r\n"
+	if len(execPropagate) > 0 {
+		block := append([][]string{{"MULTI"}}, execPropagate...)
+		replication.PropagateCommands(append(block, []string{"EXEC"}))
 	}
-
-	key := args[1]
-	entry, exists := GetEntry(key)
-	if !exists {
-		entry = core.StoreEntry{Data: "0", Type: "string"}
-	}
-
-	val, err := strconv.Atoi(entry.Data.(string))
-	if err != nil {
-		return "-ERR value is not an integer or out of range\r\n"
-	}
-
-	val++
-	SetKey(key, strconv.Itoa(val), 0)
-	replication.PropagateCommand("INCR", args[1:])
-	return fmt.Sprintf(":%d\r\n", val)
-}
-
-func handleGetInTransaction(conn net.Conn, args []string) string {
-	if len(args) < 2 {
-		return "-ERR wrong number of arguments for 'GET' command\r\n"
-	}
-
-	key := args[1]
-	entry, exists := GetEntry(key)
-	if !exists {
-		return "$-1\r\n"
-	}
-
-	value, ok := entry.Data.(string)
-	if !ok {
-		return "$-1\r\n"
-	}
-
-	return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	execPropagate = nil
+	preventPropagation(c) // what it ran went out above
+	return nil
 }
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/replication"
+)
+
+func TestMultiAndWatchAbortTransaction(t *testing.T) {
+	for _, args := range [][]string{{"MULTI"}, {"WATCH", "exec:key"}} {
+		c := newTestClient()
+		c.do("MULTI")
+		c.do("SET", "exec:key", "1")
+		if got, want := c.do(args...), "-ERR Command not allowed inside a transaction\r\n"; got != want {
+			t.Errorf("%v inside MULTI = %q, want %q", args, got, want)
+		}
+		if got, want := c.do("EXEC"), "-EXECABORT Transaction discarded because of previous errors.\r\n"; got != want {
+			t.Errorf("EXEC after %v = %q, want %q", args, got, want)
+		}
+	}
+}
+
+func TestWaitInsideExecDoesNotBlock(t *testing.T) {
+	// a replica that never acknowledges anything
+	conn, peer := net.Pipe()
+	go io.Copy(io.Discard, peer)
+	replication.AddReplica(conn)
+
+	c := newTestClient()
+	c.do("MULTI")
+	c.do("SET", "exec:wait", "1")
+	c.do("WAIT", "5", "3000")
+	done := make(chan string)
+	go func() { done <- c.do("EXEC") }()
+	time.Sleep(20 * time.Millisecond) // let EXEC start
+
+	other := newTestClient()
+	start := time.Now()
+	other.do("GET", "exec:wait")
+	var reply string
+	select {
+	case reply = <-done:
+	case <-time.After(time.Second):
+		t.Fatal("EXEC containing WAIT blocked")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GET took %v while a transaction with WAIT ran", elapsed)
+	}
+	if !strings.HasPrefix(reply, "*2\r\n+OK\r\n:") {
+		t.Errorf("EXEC = %q, want OK and the number of replicas", reply)
+	}
+}
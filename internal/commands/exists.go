@@ -0,0 +1,18 @@
+package commands
+
+// HandleExists counts how many of the given keys exist; a key given twice
+// counts twice.
+func HandleExists(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	count := 0
+	for _, key := range args[1:] {
+		if _, exists := lookupKeyRead(key); exists {
+			count++
+		}
+	}
+
+	c.Reply.Integer(int64(count))
+	return nil
+}
@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	expireNX = 1 << iota // only if the key has no TTL
+	expireXX             // only if the key has a TTL
+	expireGT             // only if the new TTL is greater
+	expireLT             // only if the new TTL is less
+)
+
+func HandleExpire(c *Client, args []string) error {
+	return expireGeneric(c, args, time.Now().UnixMilli(), 1000)
+}
+
+func HandlePexpire(c *Client, args []string) error {
+	return expireGeneric(c, args, time.Now().UnixMilli(), 1)
+}
+
+func HandleExpireAt(c *Client, args []string) error {
+	return expireGeneric(c, args, 0, 1000)
+}
+
+func HandlePexpireAt(c *Client, args []string) error {
+	return expireGeneric(c, args, 0, 1)
+}
+
+// expireGeneric implements the EXPIRE family. The time argument is in
+// units of unit ms and relative to basetime (0 for the *AT variants). A
+// deadline that has already passed deletes the key. Either way the command
+// is propagated with an absolute time (PEXPIREAT, or DEL), so replicas
+// don't depend on when they happen to apply it.
+func expireGeneric(c *Client, args []string, basetime, unit int64) error {
+	key := args[1]
+
+	flags, err := parseExpireFlags(args[3:])
+	if err != nil {
+		return err
+	}
+
+	when, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return errNotInteger
+	}
+	if when > math.MaxInt64/unit || when < math.MinInt64/unit || when*unit > math.MaxInt64-basetime {
+		return fmt.Errorf("ERR invalid expire time in '%s' command", strings.ToLower(args[0]))
+	}
+	when = when*unit + basetime
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	entry, exists := lookupKeyWrite(key)
+	if !exists || !expireAllowed(flags, entry.ExpiresAt, when) {
+		preventPropagation(c)
+		c.Reply.Integer(0)
+		return nil
+	}
+
+	// a replica applies the master's PEXPIREAT as is and waits for its DEL
+	if when <= time.Now().UnixMilli() && configs["role"] != "slave" {
+		deleteKey(key)
+		propagateAs(c, "DEL", key)
+		c.Reply.Integer(1)
+		return nil
+	}
+
+	setExpire(key, when)
+	propagateAs(c, append([]string{"PEXPIREAT", key, strconv.FormatInt(when, 10)}, args[3:]...)...)
+	c.Reply.Integer(1)
+	return nil
+}
+
+func parseExpireFlags(options []string) (int, error) {
+	flags := 0
+	for _, opt := range options {
+		switch strings.ToUpper(opt) {
+		case "NX":
+			flags |= expireNX
+		case "XX":
+			flags |= expireXX
+		case "GT":
+			flags |= expireGT
+		case "LT":
+			flags |= expireLT
+		default:
+			return 0, fmt.Errorf("ERR Unsupported option %s", opt)
+		}
+	}
+
+	if flags&expireNX != 0 && flags&(expireXX|expireGT|expireLT) != 0 {
+		return 0, fmt.Errorf("ERR NX and XX, GT or LT options at the same time are not compatible")
+	}
+	if flags&expireGT != 0 && flags&expireLT != 0 {
+		return 0, fmt.Errorf("ERR GT and LT options at the same time are not compatible")
+	}
+	return flags, nil
+}
+
+// expireAllowed checks the NX/XX/GT/LT conditions against the key's current
+// deadline (0 when it has none, which counts as an infinite TTL).
+func expireAllowed(flags int, current, when int64) bool {
+	switch {
+	case flags&expireNX != 0:
+		return current == 0
+	case flags&expireXX != 0 && current == 0:
+		return false
+	case flags&expireGT != 0:
+		return current != 0 && when > current
+	case flags&expireLT != 0:
+		return current == 0 || when < current
+	}
+	return true
+}
@@ -0,0 +1,24 @@
+package commands
+
+import "strings"
+
+// HandleFlushall removes every key: FLUSHALL [ASYNC|SYNC]. There is a
+// single database, so FLUSHDB is the same command. The old keyspace is
+// simply dropped either way, leaving it to the garbage collector.
+func HandleFlushall(c *Client, args []string) error {
+	if len(args) > 2 {
+		return errSyntax
+	}
+	if len(args) == 2 {
+		if mode := strings.ToUpper(args[1]); mode != "ASYNC" && mode != "SYNC" {
+			return errSyntax
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	flushStore()
+	c.Reply.OK()
+	return nil
+}
@@ -0,0 +1,400 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+)
+
+// Geo sets are sorted sets whose scores are 52-bit geohashes: longitude
+// and latitude are each quantized to 26 bits, and their bits interleaved
+// so that nearby points get nearby scores. A search covers the 3x3 box of
+// geohash cells around its center, cells big enough for the shape to fit,
+// and filters what it finds there by distance.
+//
+// The math is Redis's, so that scores, distances and search results match
+// it exactly.
+
+const (
+	geoStepMax = 26 // bits per coordinate
+	geoLatMin  = -85.05112878
+	geoLatMax  = 85.05112878
+	geoLongMin = -180.0
+	geoLongMax = 180.0
+
+	earthRadiusMeters = 6372797.560856
+	mercatorMax       = 20037726.37
+)
+
+// geoRange is the range of a coordinate, or of a geohash cell along it.
+type geoRange struct {
+	min, max float64
+}
+
+var (
+	geoLongRange = geoRange{geoLongMin, geoLongMax}
+	geoLatRange  = geoRange{geoLatMin, geoLatMax}
+)
+
+// geoHash is a geohash of step bits per coordinate, latitude in the even
+// bits and longitude in the odd ones. The zero value stands for no cell.
+type geoHash struct {
+	bits uint64
+	step uint
+}
+
+func (h geoHash) isZero() bool {
+	return h.bits == 0 && h.step == 0
+}
+
+// align52 returns h as a 52-bit geohash score: the first one in its cell.
+func (h geoHash) align52() uint64 {
+	return h.bits << (52 - h.step*2)
+}
+
+// geoArea is the cell of a geohash.
+type geoArea struct {
+	long, lat geoRange
+}
+
+// geohashEncode returns the geohash of step bits of a point, false if it's
+// out of range.
+func geohashEncode(longRange, latRange geoRange, longitude, latitude float64, step uint) (geoHash, bool) {
+	if longitude > geoLongMax || longitude < geoLongMin || latitude > geoLatMax || latitude < geoLatMin {
+		return geoHash{}, false
+	}
+	if latitude < latRange.min || latitude > latRange.max || longitude < longRange.min || longitude > longRange.max {
+		return geoHash{}, false
+	}
+	latOffset := (latitude - latRange.min) / (latRange.max - latRange.min)
+	longOffset := (longitude - longRange.min) / (longRange.max - longRange.min)
+	latOffset *= float64(uint64(1) << step)
+	longOffset *= float64(uint64(1) << step)
+	return geoHash{interleave64(uint32(latOffset), uint32(longOffset)), step}, true
+}
+
+// geohashEncodeWGS84 encodes a point with the ranges of geo sets.
+func geohashEncodeWGS84(longitude, latitude float64) (geoHash, bool) {
+	return geohashEncode(geoLongRange, geoLatRange, longitude, latitude, geoStepMax)
+}
+
+func geohashDecode(h geoHash) geoArea {
+	lat, long := deinterleave64(h.bits)
+	cells := float64(uint64(1) << h.step)
+	latScale := geoLatRange.max - geoLatRange.min
+	longScale := geoLongRange.max - geoLongRange.min
+	return geoArea{
+		lat: geoRange{
+			geoLatRange.min + float64(lat)/cells*latScale,
+			geoLatRange.min + float64(lat+1)/cells*latScale,
+		},
+		long: geoRange{
+			geoLongRange.min + float64(long)/cells*longScale,
+			geoLongRange.min + float64(long+1)/cells*longScale,
+		},
+	}
+}
+
+// geoDecodeScore returns the longitude and latitude of a geo set score:
+// the center of its cell.
+func geoDecodeScore(score float64) (float64, float64) {
+	area := geohashDecode(geoHash{uint64(score), geoStepMax})
+	longitude := min(max((area.long.min+area.long.max)/2, geoLongMin), geoLongMax)
+	latitude := min(max((area.lat.min+area.lat.max)/2, geoLatMin), geoLatMax)
+	return longitude, latitude
+}
+
+// interleave64 spreads the bits of x over the even bits of the result and
+// those of y over the odd ones.
+func interleave64(x, y uint32) uint64 {
+	return spreadBits(x) | spreadBits(y)<<1
+}
+
+// deinterleave64 is the inverse of interleave64.
+func deinterleave64(v uint64) (uint32, uint32) {
+	return squashBits(v), squashBits(v >> 1)
+}
+
+func spreadBits(v uint32) uint64 {
+	x := uint64(v)
+	x = (x | x<<16) & 0x0000FFFF0000FFFF
+	x = (x | x<<8) & 0x00FF00FF00FF00FF
+	x = (x | x<<4) & 0x0F0F0F0F0F0F0F0F
+	x = (x | x<<2) & 0x3333333333333333
+	x = (x | x<<1) & 0x5555555555555555
+	return x
+}
+
+func squashBits(x uint64) uint32 {
+	x &= 0x5555555555555555
+	x = (x | x>>1) & 0x3333333333333333
+	x = (x | x>>2) & 0x0F0F0F0F0F0F0F0F
+	x = (x | x>>4) & 0x00FF00FF00FF00FF
+	x = (x | x>>8) & 0x0000FFFF0000FFFF
+	x = (x | x>>16) & 0x00000000FFFFFFFF
+	return uint32(x)
+}
+
+// geohashMove returns the cell dx cells east (or west, if negative) and dy
+// north (or south) of h, wrapping around.
+func geohashMove(h geoHash, dx, dy int) geoHash {
+	const evenBits, oddBits = 0x5555555555555555, 0xaaaaaaaaaaaaaaaa
+	shift := 64 - h.step*2
+	move := func(mask uint64, d int) uint64 {
+		v := h.bits & mask
+		zz := (^mask) >> shift
+		switch {
+		case d > 0:
+			v += zz + 1
+		case d < 0:
+			v |= zz
+			v -= zz + 1
+		default:
+			return v
+		}
+		return v & (mask >> shift)
+	}
+	// longitude is in the odd bits, latitude in the even ones
+	return geoHash{move(oddBits, dx) | move(evenBits, dy), h.step}
+}
+
+func degRad(deg float64) float64 { return deg * (math.Pi / 180) }
+func radDeg(rad float64) float64 { return rad / (math.Pi / 180) }
+
+// geoLatDistance is the distance in meters between two latitudes.
+func geoLatDistance(lat1, lat2 float64) float64 {
+	return earthRadiusMeters * math.Abs(degRad(lat2)-degRad(lat1))
+}
+
+// geoDistance is the haversine distance in meters between two points.
+func geoDistance(long1, lat1, long2, lat2 float64) float64 {
+	long1r, long2r := degRad(long1), degRad(long2)
+	v := math.Sin((long2r - long1r) / 2)
+	if v == 0 {
+		return geoLatDistance(lat1, lat2)
+	}
+	lat1r, lat2r := degRad(lat1), degRad(lat2)
+	u := math.Sin((lat2r - lat1r) / 2)
+	a := u*u + math.Cos(lat1r)*math.Cos(lat2r)*v*v
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(a))
+}
+
+// geoShape is the area a search covers: a circle of the given radius, or
+// a box of the given width and height, centered on longitude, latitude.
+// Its sizes are in the unit given, which is conversion meters.
+type geoShape struct {
+	longitude, latitude float64
+	box                 bool
+	radius              float64
+	width, height       float64
+	conversion          float64
+}
+
+// contains reports whether the point is in the shape, and how far it is
+// from its center in meters.
+func (s *geoShape) contains(longitude, latitude float64) (float64, bool) {
+	if !s.box {
+		distance := geoDistance(s.longitude, s.latitude, longitude, latitude)
+		return distance, distance <= s.radius*s.conversion
+	}
+	// the latitude distance is the cheapest, so it goes first
+	if geoLatDistance(latitude, s.latitude) > s.height*s.conversion/2 {
+		return 0, false
+	}
+	if geoDistance(longitude, latitude, s.longitude, latitude) > s.width*s.conversion/2 {
+		return 0, false
+	}
+	return geoDistance(s.longitude, s.latitude, longitude, latitude), true
+}
+
+// boundingBox returns the longitudes and latitudes bounding the shape.
+func (s *geoShape) boundingBox() (minLong, minLat, maxLong, maxLat float64) {
+	height, width := s.radius, s.radius
+	if s.box {
+		height, width = s.height/2, s.width/2
+	}
+	height *= s.conversion
+	width *= s.conversion
+
+	latDelta := radDeg(height / earthRadiusMeters)
+	longDeltaTop := radDeg(width / earthRadiusMeters / math.Cos(degRad(s.latitude+latDelta)))
+	longDeltaBottom := radDeg(width / earthRadiusMeters / math.Cos(degRad(s.latitude-latDelta)))
+	// the box is widest on the side towards the pole
+	longDelta := longDeltaTop
+	if s.latitude < 0 {
+		longDelta = longDeltaBottom
+	}
+	return s.longitude - longDelta, s.latitude - latDelta, s.longitude + longDelta, s.latitude + latDelta
+}
+
+// geoStepsForRadius returns the geohash precision whose cells are about as
+// big as a search of radius meters around latitude.
+func geoStepsForRadius(radius, latitude float64) uint {
+	if radius == 0 {
+		return geoStepMax
+	}
+	step := 1
+	for ; radius < mercatorMax; radius *= 2 {
+		step++
+	}
+	step -= 2 // so that the radius fits in most cases
+	// cells get narrower towards the poles
+	if latitude > 66 || latitude < -66 {
+		step--
+		if latitude > 80 || latitude < -80 {
+			step--
+		}
+	}
+	return uint(min(max(step, 1), geoStepMax))
+}
+
+// cells returns the geohash cells to search for the shape: the one of its
+// center, then the ones north, south, east, west, north-east, north-west,
+// south-east and south-west of it. Those that can't hold any of it are
+// left zero.
+func (s *geoShape) cells() [9]geoHash {
+	minLong, minLat, maxLong, maxLat := s.boundingBox()
+	radius := s.radius
+	if s.box {
+		radius = math.Sqrt((s.width/2)*(s.width/2) + (s.height/2)*(s.height/2))
+	}
+	steps := geoStepsForRadius(radius*s.conversion, s.latitude)
+
+	center, _ := geohashEncode(geoLongRange, geoLatRange, s.longitude, s.latitude, steps)
+	neighbor := func(dx, dy int) geoArea { return geohashDecode(geohashMove(center, dx, dy)) }
+	// the estimate may be off at the edges of the search: if the cells
+	// around the center don't reach them, use bigger ones
+	if steps > 1 && (neighbor(0, 1).lat.max < maxLat || neighbor(0, -1).lat.min > minLat ||
+		neighbor(1, 0).long.max < maxLong || neighbor(-1, 0).long.min > minLong) {
+		steps--
+		center, _ = geohashEncode(geoLongRange, geoLatRange, s.longitude, s.latitude, steps)
+	}
+	area := geohashDecode(center)
+
+	cells := [9]geoHash{center}
+	for i, d := range [8][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}, {1, 1}, {-1, 1}, {1, -1}, {-1, -1}} {
+		cells[i+1] = geohashMove(center, d[0], d[1])
+	}
+	if steps >= 2 {
+		// north is 1, 5, 6; south 2, 7, 8; east 3, 5, 7; west 4, 6, 8
+		drop := func(indexes ...int) {
+			for _, i := range indexes {
+				cells[i] = geoHash{}
+			}
+		}
+		if area.lat.min < minLat {
+			drop(2, 7, 8)
+		}
+		if area.lat.max > maxLat {
+			drop(1, 5, 6)
+		}
+		if area.long.min < minLong {
+			drop(4, 6, 8)
+		}
+		if area.long.max > maxLong {
+			drop(3, 5, 7)
+		}
+	}
+	return cells
+}
+
+// geoPoint is a member found by a search.
+type geoPoint struct {
+	member              string
+	score               float64
+	longitude, latitude float64
+	distance            float64 // from the center, in meters
+}
+
+// geoSearch returns the members of zset in the shape, unordered. With a
+// limit above 0 it stops once it has found that many.
+func geoSearch(zset *core.SortedSet, shape *geoShape, limit int) []geoPoint {
+	var points []geoPoint
+	cells := shape.cells()
+	last := 0
+	for i, cell := range cells {
+		if cell.isZero() {
+			continue
+		}
+		// huge radiuses may make neighbors the same cell; like Redis,
+		// this doesn't compare with the center
+		if last > 0 && cell == cells[last] {
+			continue
+		}
+		if limit > 0 && len(points) >= limit {
+			break
+		}
+		last = i
+
+		first := cell.align52()
+		cell.bits++
+		r := core.ScoreRange{Min: float64(first), Max: float64(cell.align52()), MaxEx: true}
+		zset.RangeByScore(r, false, 0, -1, func(member string, score float64) bool {
+			longitude, latitude := geoDecodeScore(score)
+			if distance, ok := shape.contains(longitude, latitude); ok {
+				points = append(points, geoPoint{member, score, longitude, latitude, distance})
+			}
+			return limit <= 0 || len(points) < limit
+		})
+	}
+	return points
+}
+
+// Units of distance, in meters.
+var geoUnits = map[string]float64{"m": 1, "km": 1000, "ft": 0.3048, "mi": 1609.34}
+
+func parseGeoUnit(arg string) (float64, error) {
+	conversion, ok := geoUnits[strings.ToLower(arg)]
+	if !ok {
+		return 0, errors.New("ERR unsupported unit provided. please use M, KM, FT, MI")
+	}
+	return conversion, nil
+}
+
+// parseLongLat parses a longitude and a latitude, which must be in the
+// range geohashes can encode.
+func parseLongLat(longArg, latArg string) (float64, float64, error) {
+	longitude, err := parseFloat(longArg)
+	if err != nil {
+		return 0, 0, err
+	}
+	latitude, err := parseFloat(latArg)
+	if err != nil {
+		return 0, 0, err
+	}
+	if longitude < geoLongMin || longitude > geoLongMax || latitude < geoLatMin || latitude > geoLatMax {
+		return 0, 0, fmt.Errorf("ERR invalid longitude,latitude pair %f,%f", longitude, latitude)
+	}
+	return longitude, latitude, nil
+}
+
+// parseGeoDistance parses a non-negative distance, reporting what it is in
+// the error if it isn't a number.
+func parseGeoDistance(arg, what string) (float64, error) {
+	d, err := strconv.ParseFloat(arg, 64)
+	if err != nil || math.IsNaN(d) {
+		return 0, errors.New("ERR need numeric " + what)
+	}
+	return d, nil
+}
+
+// formatGeoDistance formats a distance as Redis replies with them, with 4
+// decimals.
+func formatGeoDistance(d float64) string {
+	return strconv.FormatFloat(d, 'f', 4, 64)
+}
+
+// replyGeoCoordinate writes a coordinate with up to 17 decimals, without
+// trailing zeros.
+func replyGeoCoordinate(c *Client, f float64) {
+	s := strconv.FormatFloat(f, 'f', 17, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" {
+		s = "0"
+	}
+	c.Reply.FormattedDouble(s)
+}
@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"strconv"
+	"strings"
+)
+
+// HandleGeoadd adds members to a geo set, or moves them:
+// GEOADD key [NX|XX] [CH] longitude latitude member [...]. It's ZADD with
+// the geohash of each point as its score, and replicates as that ZADD.
+func HandleGeoadd(c *Client, args []string) error {
+	i := 2
+	nx, xx := false, false
+options:
+	for ; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		case "CH":
+		default:
+			break options
+		}
+	}
+	if (len(args)-i)%3 != 0 || (nx && xx) {
+		return errSyntax
+	}
+
+	zaddArgs := append([]string{"ZADD"}, args[1:i]...)
+	for ; i < len(args); i += 3 {
+		longitude, latitude, err := parseLongLat(args[i], args[i+1])
+		if err != nil {
+			return err
+		}
+		hash, _ := geohashEncodeWGS84(longitude, latitude)
+		zaddArgs = append(zaddArgs, strconv.FormatUint(hash.align52(), 10), args[i+2])
+	}
+
+	if err := HandleZadd(c, zaddArgs); err != nil {
+		return err
+	}
+	if c.Propagate == nil {
+		propagateAs(c, zaddArgs...)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package commands
+
+// HandleGeodist replies with the distance between two members of a geo
+// set, in meters or the unit given: GEODIST key member1 member2
+// [M|KM|FT|MI]. It replies nil if either is missing.
+func HandleGeodist(c *Client, args []string) error {
+	conversion := 1.0
+	switch {
+	case len(args) == 5:
+		var err error
+		if conversion, err = parseGeoUnit(args[4]); err != nil {
+			return err
+		}
+	case len(args) > 5:
+		return errSyntax
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	zset, err := lookupZsetRead(args[1])
+	if err != nil {
+		return err
+	}
+	if zset == nil {
+		c.Reply.Null()
+		return nil
+	}
+	score1, ok1 := zset.Score(args[2])
+	score2, ok2 := zset.Score(args[3])
+	if !ok1 || !ok2 {
+		c.Reply.Null()
+		return nil
+	}
+	long1, lat1 := geoDecodeScore(score1)
+	long2, lat2 := geoDecodeScore(score2)
+	c.Reply.Bulk(formatGeoDistance(geoDistance(long1, lat1, long2, lat2) / conversion))
+	return nil
+}
@@ -0,0 +1,43 @@
+package commands
+
+// geoAlphabet is the base32 alphabet of geohash strings.
+const geoAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// HandleGeohash replies with the standard 11 character geohash string of
+// members of a geo set, nil for those that are missing: GEOHASH key
+// [member ...]. Geo sets encode latitudes between -85 and 85 rather than
+// -90 and 90, so each point is decoded and encoded again. The last
+// character is always 0, as 52 bits only make 10.4 characters.
+func HandleGeohash(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	zset, err := lookupZsetRead(args[1])
+	if err != nil {
+		return err
+	}
+	c.Reply.ArrayLen(len(args) - 2)
+	for _, member := range args[2:] {
+		var score float64
+		ok := false
+		if zset != nil {
+			score, ok = zset.Score(member)
+		}
+		if !ok {
+			c.Reply.Null()
+			continue
+		}
+		longitude, latitude := geoDecodeScore(score)
+		hash, _ := geohashEncode(geoRange{-180, 180}, geoRange{-90, 90}, longitude, latitude, geoStepMax)
+		var buf [11]byte
+		for i := range buf {
+			idx := 0
+			if i < 10 {
+				idx = int(hash.bits>>(52-(i+1)*5)) & 0x1f
+			}
+			buf[i] = geoAlphabet[idx]
+		}
+		c.Reply.Bulk(string(buf[:]))
+	}
+	return nil
+}
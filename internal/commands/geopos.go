@@ -0,0 +1,32 @@
+package commands
+
+// HandleGeopos replies with the longitude and latitude of members of a geo
+// set, nil for those that are missing: GEOPOS key [member ...]. They are
+// those of the center of the member's geohash cell, so they may differ
+// slightly from what was added.
+func HandleGeopos(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	zset, err := lookupZsetRead(args[1])
+	if err != nil {
+		return err
+	}
+	c.Reply.ArrayLen(len(args) - 2)
+	for _, member := range args[2:] {
+		var score float64
+		ok := false
+		if zset != nil {
+			score, ok = zset.Score(member)
+		}
+		if !ok {
+			c.Reply.NullArray()
+			continue
+		}
+		longitude, latitude := geoDecodeScore(score)
+		c.Reply.ArrayLen(2)
+		replyGeoCoordinate(c, longitude)
+		replyGeoCoordinate(c, latitude)
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+)
+
+func HandleGeosearch(c *Client, args []string) error {
+	return geosearchGeneric(c, args, "")
+}
+
+func HandleGeosearchstore(c *Client, args []string) error {
+	return geosearchGeneric(c, append([]string{args[0]}, args[2:]...), args[1])
+}
+
+// geosearchGeneric implements GEOSEARCH key FROMMEMBER member|FROMLONLAT
+// longitude latitude BYRADIUS radius unit|BYBOX width height unit
+// [ASC|DESC] [COUNT count [ANY]] [WITHCOORD] [WITHDIST] [WITHHASH]. It
+// replies with the members in the area, nearest first with ASC, and with
+// their coordinates, distance and score if asked to. COUNT keeps the
+// nearest count of them, or with ANY the first count found.
+//
+// GEOSEARCHSTORE destination source ... [STOREDIST] stores them in
+// destination instead, scored by their distance with STOREDIST, and
+// replies with how many there are.
+func geosearchGeneric(c *Client, args []string, destination string) error {
+	if destination == "" {
+		mu.RLock()
+		defer mu.RUnlock()
+	} else {
+		mu.Lock()
+		defer mu.Unlock()
+	}
+
+	zset, err := lookupZsetRead(args[1])
+	if err != nil {
+		return err
+	}
+
+	var shape geoShape
+	withDist, withHash, withCoord, storeDist := false, false, false, false
+	fromMember, fromLonLat, byRadius, byBox := false, false, false, false
+	anyMatch := false
+	order := 0 // 1 ascending, -1 descending
+	count := 0
+	for i := 2; i < len(args); i++ {
+		remaining := len(args) - i - 1
+		switch opt := strings.ToUpper(args[i]); {
+		case opt == "WITHDIST":
+			withDist = true
+		case opt == "WITHHASH":
+			withHash = true
+		case opt == "WITHCOORD":
+			withCoord = true
+		case opt == "ANY":
+			anyMatch = true
+		case opt == "ASC":
+			order = 1
+		case opt == "DESC":
+			order = -1
+		case opt == "COUNT" && remaining >= 1:
+			n, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil {
+				return errNotInteger
+			}
+			if n <= 0 {
+				return errors.New("ERR COUNT must be > 0")
+			}
+			count = int(n)
+			i++
+		case opt == "STOREDIST" && destination != "":
+			storeDist = true
+		case opt == "FROMMEMBER" && remaining >= 1 && !fromLonLat:
+			fromMember = true
+			i++
+			if zset == nil {
+				continue // replied to below, once the options are checked
+			}
+			score, ok := zset.Score(args[i])
+			if !ok {
+				return errors.New("ERR could not decode requested zset member")
+			}
+			shape.longitude, shape.latitude = geoDecodeScore(score)
+		case opt == "FROMLONLAT" && remaining >= 2 && !fromMember:
+			if shape.longitude, shape.latitude, err = parseLongLat(args[i+1], args[i+2]); err != nil {
+				return err
+			}
+			fromLonLat = true
+			i += 2
+		case opt == "BYRADIUS" && remaining >= 2 && !byBox:
+			if shape.radius, err = parseGeoDistance(args[i+1], "radius"); err != nil {
+				return err
+			}
+			if shape.radius < 0 {
+				return errors.New("ERR radius cannot be negative")
+			}
+			if shape.conversion, err = parseGeoUnit(args[i+2]); err != nil {
+				return err
+			}
+			byRadius = true
+			i += 2
+		case opt == "BYBOX" && remaining >= 3 && !byRadius:
+			if shape.width, err = parseGeoDistance(args[i+1], "width"); err != nil {
+				return err
+			}
+			if shape.height, err = parseGeoDistance(args[i+2], "height"); err != nil {
+				return err
+			}
+			if shape.width < 0 || shape.height < 0 {
+				return errors.New("ERR height or width cannot be negative")
+			}
+			if shape.conversion, err = parseGeoUnit(args[i+3]); err != nil {
+				return err
+			}
+			shape.box = true
+			byBox = true
+			i += 3
+		default:
+			return errSyntax
+		}
+	}
+
+	name := strings.ToLower(args[0])
+	switch {
+	case destination != "" && (withDist || withHash || withCoord):
+		return errors.New("ERR GEOSEARCHSTORE is not compatible with WITHDIST, WITHHASH and WITHCOORD options")
+	case !fromMember && !fromLonLat:
+		return fmt.Errorf("ERR exactly one of FROMMEMBER or FROMLONLAT can be specified for %s", name)
+	case !byRadius && !byBox:
+		return fmt.Errorf("ERR exactly one of BYRADIUS and BYBOX can be specified for %s", name)
+	case anyMatch && count == 0:
+		return errors.New("ERR the ANY argument requires COUNT argument")
+	}
+
+	if zset == nil {
+		if destination == "" {
+			c.Reply.ArrayLen(0)
+			return nil
+		}
+		if !deleteKey(destination) {
+			preventPropagation(c)
+		}
+		c.Reply.Integer(0)
+		return nil
+	}
+
+	// COUNT keeps the nearest points, unless ANY says any will do
+	if count > 0 && order == 0 && !anyMatch {
+		order = 1
+	}
+	limit := 0
+	if anyMatch {
+		limit = count
+	}
+	points := geoSearch(zset, &shape, limit)
+	if order != 0 {
+		slices.SortStableFunc(points, func(a, b geoPoint) int {
+			if a.distance == b.distance {
+				return 0
+			}
+			if (a.distance < b.distance) == (order > 0) {
+				return -1
+			}
+			return 1
+		})
+	}
+	if count > 0 && len(points) > count {
+		points = points[:count]
+	}
+
+	if destination != "" {
+		result := core.NewSortedSet()
+		for _, p := range points {
+			score := p.score
+			if storeDist {
+				score = p.distance / shape.conversion
+			}
+			result.Add(p.member, score)
+		}
+		if result.Len() > 0 {
+			storeZset(destination, result)
+		} else if !deleteKey(destination) {
+			preventPropagation(c)
+		}
+		c.Reply.Integer(int64(len(points)))
+		return nil
+	}
+
+	options := 0
+	for _, with := range []bool{withDist, withHash, withCoord} {
+		if with {
+			options++
+		}
+	}
+	c.Reply.ArrayLen(len(points))
+	for _, p := range points {
+		if options > 0 {
+			c.Reply.ArrayLen(options + 1)
+		}
+		c.Reply.Bulk(p.member)
+		if withDist {
+			c.Reply.Bulk(formatGeoDistance(p.distance / shape.conversion))
+		}
+		if withHash {
+			c.Reply.Integer(int64(p.score))
+		}
+		if withCoord {
+			c.Reply.ArrayLen(2)
+			replyGeoCoordinate(c, p.longitude)
+			replyGeoCoordinate(c, p.latitude)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package commands
+
+// HandleGetdel replies with the string at key and deletes it: GETDEL key.
+func HandleGetdel(c *Client, args []string) error {
+	key := args[1]
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	value, exists, err := lookupStringWrite(key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		preventPropagation(c)
+		c.Reply.Null()
+		return nil
+	}
+
+	deleteKey(key)
+	propagateAs(c, "DEL", key)
+	c.Reply.Bulk(value)
+	return nil
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HandleGetex replies with the string at key, changing its TTL with one of
+// the options: GETEX key [EX s | PX ms | EXAT ts | PXAT ms-ts | PERSIST].
+// Like EXPIRE it's propagated with an absolute time, or as a DEL if that
+// time has passed.
+func HandleGetex(c *Client, args []string) error {
+	key := args[1]
+	var expireAt int64
+	persist := false
+	for i := 2; i < len(args); i++ {
+		opt := strings.ToUpper(args[i])
+		switch {
+		case opt == "PERSIST" && expireAt == 0 && !persist:
+			persist = true
+		case (opt == "EX" || opt == "PX" || opt == "EXAT" || opt == "PXAT") &&
+			expireAt == 0 && !persist && i+1 < len(args):
+			i++
+			at, err := parseSetExpire("getex", opt, args[i])
+			if err != nil {
+				return err
+			}
+			expireAt = at
+		default:
+			return errSyntax
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	value, exists, err := lookupStringWrite(key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		preventPropagation(c)
+		c.Reply.Null()
+		return nil
+	}
+
+	entry, _ := store.Get(key)
+	switch {
+	case expireAt > 0 && expireAt <= time.Now().UnixMilli() && configs["role"] != "slave":
+		deleteKey(key)
+		propagateAs(c, "DEL", key)
+	case expireAt > 0:
+		setExpire(key, expireAt)
+		propagateAs(c, "PEXPIREAT", key, strconv.FormatInt(expireAt, 10))
+	case persist && entry.ExpiresAt > 0:
+		setExpire(key, 0)
+		propagateAs(c, "PERSIST", key)
+	default:
+		preventPropagation(c)
+	}
+
+	c.Reply.Bulk(value)
+	return nil
+}
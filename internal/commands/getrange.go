@@ -0,0 +1,42 @@
+package commands
+
+// HandleGetrange replies with the substring of the string at key between
+// the offsets start and end, both included, which count from the end when
+// negative: GETRANGE key start end.
+func HandleGetrange(c *Client, args []string) error {
+	start, err := parseInt(args[2])
+	if err != nil {
+		return err
+	}
+	end, err := parseInt(args[3])
+	if err != nil {
+		return err
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	value, _, err := lookupStringRead(args[1])
+	if err != nil {
+		return err
+	}
+
+	n := len(value)
+	if start < 0 && end < 0 && start > end {
+		c.Reply.Bulk("")
+		return nil
+	}
+	if start < 0 {
+		start = max(n+start, 0)
+	}
+	if end < 0 {
+		end = max(n+end, 0)
+	}
+	end = min(end, n-1)
+	if n == 0 || start > end {
+		c.Reply.Bulk("")
+		return nil
+	}
+	c.Reply.Bulk(value[start : end+1])
+	return nil
+}
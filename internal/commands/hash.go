@@ -0,0 +1,63 @@
+package commands
+
+import "github.com/codecrafters-io/redis-starter-go/internal/models/core"
+
+// Helpers shared by the hash commands. Like the other keyspace helpers
+// they expect the caller to hold mu.
+
+// lookupHashRead returns the hash at key, nil if there is none.
+func lookupHashRead(key string) (*core.Hash, error) {
+	entry, exists := lookupKeyRead(key)
+	return hashFromEntry(entry, exists)
+}
+
+// lookupHashWrite is lookupHashRead for commands that modify the hash. It
+// reclaims the fields whose TTL passed first.
+func lookupHashWrite(key string) (*core.Hash, error) {
+	entry, exists := lookupKeyWrite(key)
+	hash, err := hashFromEntry(entry, exists)
+	if hash != nil && expireHashFields(key, hash) {
+		return nil, nil
+	}
+	return hash, err
+}
+
+func hashFromEntry(entry core.StoreEntry, exists bool) (*core.Hash, error) {
+	if !exists {
+		return nil, nil
+	}
+	if entry.Type != "hash" {
+		return nil, errWrongType
+	}
+	return entry.Data.(*core.Hash), nil
+}
+
+// lookupOrCreateHash returns the hash at key, storing a new empty one if
+// there is none.
+func lookupOrCreateHash(key string) (*core.Hash, error) {
+	hash, err := lookupHashWrite(key)
+	if err != nil || hash != nil {
+		return hash, err
+	}
+	hash = core.NewHash()
+	setKey(key, core.StoreEntry{Type: "hash", Data: hash})
+	return hash, nil
+}
+
+// deleteHashIfEmpty removes key once its hash has no fields left, as an
+// empty hash is never stored.
+func deleteHashIfEmpty(key string, hash *core.Hash) {
+	if hash.Len() == 0 {
+		deleteKey(key)
+	}
+}
+
+// updateHashFieldExpires keeps the index of hashes with field TTLs in sync
+// after the TTLs of the fields of key changed.
+func updateHashFieldExpires(key string, hash *core.Hash) {
+	if when := hash.MinExpire(); when > 0 {
+		hashFieldExpires[key] = when
+	} else {
+		delete(hashFieldExpires, key)
+	}
+}
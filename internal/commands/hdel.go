@@ -0,0 +1,34 @@
+package commands
+
+// HandleHdel removes fields from a hash, and the key with its last field:
+// HDEL key field [field ...].
+func HandleHdel(c *Client, args []string) error {
+	key := args[1]
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	hash, err := lookupHashWrite(key)
+	if err != nil {
+		return err
+	}
+
+	deleted := 0
+	if hash != nil {
+		for _, field := range args[2:] {
+			if hash.Delete(field) {
+				deleted++
+			}
+		}
+		updateHashFieldExpires(key, hash)
+		deleteHashIfEmpty(key, hash)
+	}
+
+	if deleted == 0 {
+		preventPropagation(c)
+	} else {
+		signalModifiedKey(key)
+	}
+	c.Reply.Integer(int64(deleted))
+	return nil
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/resp"
+)
+
+// serverVersion is the Redis version this server reports to clients.
+const serverVersion = "7.4.0"
+
+// HandleHello implements HELLO [protover [AUTH username password] [SETNAME clientname]].
+// It switches the connection's protocol and replies with a summary of the
+// server in the newly selected protocol.
+func HandleHello(c *Client, args []string) error {
+	proto := c.Reply.Protocol()
+
+	var username, password, name string
+	withAuth, withName := false, false
+
+	if len(args) > 1 {
+		ver, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return errors.New("ERR Protocol version is not an integer or out of range")
+		}
+		if ver < resp.RESP2 || ver > resp.RESP3 {
+			return errors.New("NOPROTO unsupported protocol version")
+		}
+		proto = int(ver)
+
+		for i := 2; i < len(args); i++ {
+			moreArgs := len(args) - 1 - i
+			switch {
+			case strings.EqualFold(args[i], "AUTH") && moreArgs >= 2:
+				username, password = args[i+1], args[i+2]
+				withAuth = true
+				i += 2
+			case strings.EqualFold(args[i], "SETNAME") && moreArgs >= 1:
+				name = args[i+1]
+				withName = true
+				i++
+			default:
+				return fmt.Errorf("ERR Syntax error in HELLO option '%s'", args[i])
+			}
+		}
+	}
+
+	if withAuth {
+		if err := authenticate(c, username, password); err != nil {
+			return err
+		}
+	} else if authRequired(c) {
+		return errors.New("NOAUTH HELLO must be called with the client already authenticated, otherwise the HELLO <proto> AUTH <user> <pass> option can be used to authenticate the client and select the RESP protocol version at the same time")
+	}
+	if withName {
+		if err := setClientName(c, name); err != nil {
+			return err
+		}
+	}
+
+	c.Reply.SetProtocol(proto)
+
+	role, _ := GetConfig("role")
+	if role == "slave" {
+		role = "replica"
+	}
+
+	c.Reply.MapLen(7)
+	c.Reply.Bulk("server")
+	c.Reply.Bulk("redis")
+	c.Reply.Bulk("version")
+	c.Reply.Bulk(serverVersion)
+	c.Reply.Bulk("proto")
+	c.Reply.Integer(int64(proto))
+	c.Reply.Bulk("id")
+	c.Reply.Integer(c.ID)
+	c.Reply.Bulk("mode")
+	c.Reply.Bulk("standalone")
+	c.Reply.Bulk("role")
+	c.Reply.Bulk(role)
+	c.Reply.Bulk("modules")
+	c.Reply.ArrayLen(0)
+	return nil
+}
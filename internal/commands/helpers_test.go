@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/resp"
+)
+
+// syncBuffer collects what a test client is sent. A blocked client's reply
+// is written by whichever goroutine serves it, hence the lock.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// take returns and clears what was received so far.
+func (b *syncBuffer) take() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := b.buf.String()
+	b.buf.Reset()
+	return s
+}
+
+type testClient struct {
+	*Client
+	out *syncBuffer
+}
+
+func newTestClient() *testClient {
+	out := &syncBuffer{}
+	return &testClient{
+		Client: &Client{Reply: resp.NewWriter(out), Closed: make(chan struct{})},
+		out:    out,
+	}
+}
+
+// do runs a command and returns its raw reply.
+func (tc *testClient) do(args ...string) string {
+	Process(tc.Client, args, false)
+	tc.Reply.Flush()
+	return tc.out.take()
+}
+
+// waitBlocked waits until some client is blocked on key.
+func waitBlocked(t *testing.T, key string) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		blockedMu.Lock()
+		n := len(blockedKeys[key])
+		blockedMu.Unlock()
+		if n > 0 {
+			return
+		}
+	}
+	t.Fatalf("no client blocked on %q", key)
+}
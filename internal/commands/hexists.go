@@ -0,0 +1,21 @@
+package commands
+
+func HandleHexists(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	hash, err := lookupHashRead(args[1])
+	if err != nil {
+		return err
+	}
+	exists := false
+	if hash != nil {
+		_, exists = hash.Get(args[2])
+	}
+	if exists {
+		c.Reply.Integer(1)
+	} else {
+		c.Reply.Integer(0)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Per field replies of the hash field TTL commands.
+const (
+	hfeNoField   = -2 // no such field, or no such key
+	hfeNoTTL     = -1 // HTTL & co., HPERSIST: the field has no TTL
+	hfeNotSet    = 0  // HEXPIRE & co.: the NX/XX/GT/LT condition wasn't met
+	hfeSet       = 1
+	hfeDeleted   = 2 // HEXPIRE & co.: the time has passed, the field is gone
+	hfeMaxExpire = 1<<48 - 1
+)
+
+func HandleHexpire(c *Client, args []string) error {
+	return hexpireGeneric(c, args, time.Now().UnixMilli(), 1000)
+}
+
+func HandleHpexpire(c *Client, args []string) error {
+	return hexpireGeneric(c, args, time.Now().UnixMilli(), 1)
+}
+
+func HandleHexpireAt(c *Client, args []string) error {
+	return hexpireGeneric(c, args, 0, 1000)
+}
+
+func HandleHpexpireAt(c *Client, args []string) error {
+	return hexpireGeneric(c, args, 0, 1)
+}
+
+// hexpireGeneric implements HEXPIRE key time [NX|XX|GT|LT] FIELDS numfields
+// field [field ...] and its variants, like expireGeneric does for keys.
+// Fields whose new TTL is set are propagated with HPEXPIREAT, those
+// deleted because the time has passed with HDEL.
+func hexpireGeneric(c *Client, args []string, basetime, unit int64) error {
+	key := args[1]
+
+	when, err := parseHashExpireTime(args[0], args[2], basetime, unit)
+	if err != nil {
+		return err
+	}
+	flags, fieldsAt := 0, 3
+	if strings.ToUpper(args[3]) != "FIELDS" {
+		if flags, err = parseExpireFlags(args[3:4]); err != nil {
+			return err
+		}
+		fieldsAt++
+	}
+	fields, err := parseHashFields(args, fieldsAt)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	hash, err := lookupHashWrite(key)
+	if err != nil {
+		return err
+	}
+
+	var set, deleted []string
+	c.Reply.ArrayLen(len(fields))
+	for _, field := range fields {
+		if hash == nil {
+			c.Reply.Integer(hfeNoField)
+			continue
+		}
+		if _, exists := hash.Get(field); !exists {
+			c.Reply.Integer(hfeNoField)
+			continue
+		}
+		if !expireAllowed(flags, hash.ExpireAt(field), when) {
+			c.Reply.Integer(hfeNotSet)
+			continue
+		}
+
+		// a replica applies the master's HPEXPIREAT as is and waits for
+		// its HDEL
+		if when <= time.Now().UnixMilli() && configs["role"] != "slave" {
+			hash.Delete(field)
+			deleted = append(deleted, field)
+			c.Reply.Integer(hfeDeleted)
+			continue
+		}
+		hash.SetExpire(field, when)
+		set = append(set, field)
+		c.Reply.Integer(hfeSet)
+	}
+
+	preventPropagation(c)
+	if len(deleted) > 0 {
+		propagateAs(c, append([]string{"HDEL", key}, deleted...)...)
+	}
+	if len(set) > 0 {
+		propagateAs(c, hashFieldsCommand("HPEXPIREAT", key, strconv.FormatInt(when, 10), set)...)
+	}
+	if len(deleted) > 0 || len(set) > 0 {
+		signalModifiedKey(key)
+	}
+	if hash != nil {
+		updateHashFieldExpires(key, hash)
+		deleteHashIfEmpty(key, hash)
+	}
+	return nil
+}
+
+// parseHashExpireTime turns the time argument of a hash field TTL command,
+// in units of unit ms relative to basetime, into a unix time in ms.
+func parseHashExpireTime(command, arg string, basetime, unit int64) (int64, error) {
+	when, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return 0, errNotInteger
+	}
+	if when < 0 {
+		return 0, errors.New("ERR invalid expire time, must be >= 0")
+	}
+	if when > math.MaxInt64/unit || when*unit > math.MaxInt64-basetime || when*unit+basetime > hfeMaxExpire {
+		return 0, fmt.Errorf("ERR invalid expire time in '%s' command", strings.ToLower(command))
+	}
+	return when*unit + basetime, nil
+}
+
+// parseHashFields parses the FIELDS numfields field [field ...] that ends
+// the hash field TTL commands, starting at args[i].
+func parseHashFields(args []string, i int) ([]string, error) {
+	if i >= len(args) || strings.ToUpper(args[i]) != "FIELDS" {
+		return nil, errors.New("ERR Mandatory argument FIELDS is missing or not at the right position")
+	}
+	if i+1 >= len(args) {
+		return nil, errSyntax
+	}
+	n, err := parseInt(args[i+1])
+	if err != nil || n <= 0 {
+		return nil, errors.New("ERR Parameter `numFields` should be greater than 0")
+	}
+	if n != len(args)-i-2 {
+		return nil, errors.New("ERR The `numfields` parameter must match the number of arguments")
+	}
+	return args[i+2:], nil
+}
+
+// hashFieldsCommand builds a command to propagate: name key [arg] FIELDS
+// numfields field [field ...].
+func hashFieldsCommand(name, key, arg string, fields []string) []string {
+	argv := []string{name, key}
+	if arg != "" {
+		argv = append(argv, arg)
+	}
+	argv = append(argv, "FIELDS", strconv.Itoa(len(fields)))
+	return append(argv, fields...)
+}
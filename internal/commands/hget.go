@@ -0,0 +1,49 @@
+package commands
+
+func HandleHget(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	hash, err := lookupHashRead(args[1])
+	if err != nil {
+		return err
+	}
+	if hash == nil {
+		c.Reply.Null()
+		return nil
+	}
+	value, exists := hash.Get(args[2])
+	if !exists {
+		c.Reply.Null()
+		return nil
+	}
+	c.Reply.Bulk(value)
+	return nil
+}
+
+// HandleHmget replies with the values of the given fields, nil for the
+// ones that don't exist: HMGET key field [field ...].
+func HandleHmget(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	hash, err := lookupHashRead(args[1])
+	if err != nil {
+		return err
+	}
+
+	fields := args[2:]
+	c.Reply.ArrayLen(len(fields))
+	for _, field := range fields {
+		if hash == nil {
+			c.Reply.Null()
+			continue
+		}
+		if value, exists := hash.Get(field); exists {
+			c.Reply.Bulk(value)
+		} else {
+			c.Reply.Null()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package commands
+
+func HandleHgetall(c *Client, args []string) error {
+	return hgetallGeneric(c, args[1], true, true)
+}
+
+func HandleHkeys(c *Client, args []string) error {
+	return hgetallGeneric(c, args[1], true, false)
+}
+
+func HandleHvals(c *Client, args []string) error {
+	return hgetallGeneric(c, args[1], false, true)
+}
+
+// hgetallGeneric replies with the fields and/or values of a hash. With
+// both, it's a map of field -> value (a flat array in RESP2).
+func hgetallGeneric(c *Client, key string, fields, values bool) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	hash, err := lookupHashRead(key)
+	if err != nil {
+		return err
+	}
+	n := 0
+	if hash != nil {
+		n = hash.Len()
+	}
+
+	if fields && values {
+		c.Reply.MapLen(n)
+	} else {
+		c.Reply.ArrayLen(n)
+	}
+	if hash == nil {
+		return nil
+	}
+	hash.Range(func(field, value string) bool {
+		if fields {
+			c.Reply.Bulk(field)
+		}
+		if values {
+			c.Reply.Bulk(value)
+		}
+		return true
+	})
+	return nil
+}
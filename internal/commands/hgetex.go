@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HandleHgetex replies with the values of fields, like HMGET, and changes
+// their TTL: HGETEX key [EX seconds | PX milliseconds |
+// EXAT unix-time-seconds | PXAT unix-time-milliseconds | PERSIST]
+// FIELDS numfields field [field ...].
+func HandleHgetex(c *Client, args []string) error {
+	key := args[1]
+
+	var when int64 // 0: leave the TTLs alone
+	persist := false
+	i := 2
+	for ; i < len(args) && strings.ToUpper(args[i]) != "FIELDS"; i++ {
+		if when != 0 || persist {
+			return errors.New("ERR Only one of EX, PX, EXAT, PXAT or PERSIST arguments can be specified")
+		}
+		option := strings.ToUpper(args[i])
+		if option == "PERSIST" {
+			persist = true
+			continue
+		}
+
+		var basetime, unit int64
+		switch option {
+		case "EX":
+			basetime, unit = time.Now().UnixMilli(), 1000
+		case "PX":
+			basetime, unit = time.Now().UnixMilli(), 1
+		case "EXAT":
+			unit = 1000
+		case "PXAT":
+			unit = 1
+		default:
+			return errSyntax
+		}
+		if i+1 == len(args) {
+			return errSyntax
+		}
+		i++
+		t, err := parseHashExpireTime(args[0], args[i], basetime, unit)
+		if err != nil {
+			return err
+		}
+		when = max(t, 1) // EXAT 0 is in the past, not "no TTL"
+	}
+	fields, err := parseHashFields(args, i)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	hash, err := lookupHashWrite(key)
+	if err != nil {
+		return err
+	}
+
+	var set, deleted, persisted []string
+	expired := when != 0 && when <= time.Now().UnixMilli() && configs["role"] != "slave"
+	c.Reply.ArrayLen(len(fields))
+	for _, field := range fields {
+		if hash == nil {
+			c.Reply.Null()
+			continue
+		}
+		value, exists := hash.Get(field)
+		if !exists {
+			c.Reply.Null()
+			continue
+		}
+		c.Reply.Bulk(value)
+
+		switch {
+		case persist:
+			if hash.Persist(field) {
+				persisted = append(persisted, field)
+			}
+		case expired:
+			hash.Delete(field)
+			deleted = append(deleted, field)
+		case when != 0:
+			hash.SetExpire(field, when)
+			set = append(set, field)
+		}
+	}
+
+	preventPropagation(c)
+	if len(deleted) > 0 {
+		propagateAs(c, append([]string{"HDEL", key}, deleted...)...)
+	}
+	if len(set) > 0 {
+		propagateAs(c, hashFieldsCommand("HPEXPIREAT", key, strconv.FormatInt(when, 10), set)...)
+	}
+	if len(persisted) > 0 {
+		propagateAs(c, hashFieldsCommand("HPERSIST", key, "", persisted)...)
+	}
+	if len(deleted) > 0 || len(set) > 0 || len(persisted) > 0 {
+		signalModifiedKey(key)
+	}
+	if hash != nil {
+		updateHashFieldExpires(key, hash)
+		deleteHashIfEmpty(key, hash)
+	}
+	return nil
+}
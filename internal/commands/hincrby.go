@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"errors"
+	"math"
+	"strconv"
+)
+
+// HandleHincrby adds an integer to a field, which is created as 0 if it
+// doesn't exist: HINCRBY key field increment.
+func HandleHincrby(c *Client, args []string) error {
+	key, field := args[1], args[2]
+	incr, err := strconv.ParseInt(args[3], 10, 64)
+	if err != nil {
+		return errNotInteger
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	hash, err := lookupOrCreateHash(key)
+	if err != nil {
+		return err
+	}
+
+	var current int64
+	if value, exists := hash.Get(field); exists {
+		current, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return errors.New("ERR hash value is not an integer")
+		}
+	}
+	if (incr > 0 && current > math.MaxInt64-incr) || (incr < 0 && current < math.MinInt64-incr) {
+		return errors.New("ERR increment or decrement would overflow")
+	}
+
+	current += incr
+	hash.SetKeepTTL(field, strconv.FormatInt(current, 10))
+	signalModifiedKey(key)
+	c.Reply.Integer(current)
+	return nil
+}
+
+// HandleHincrbyfloat is HINCRBY for floating point increments. It's
+// replicated as an HSET of the result, so that replicas don't have to
+// reproduce our float arithmetic.
+func HandleHincrbyfloat(c *Client, args []string) error {
+	key, field := args[1], args[2]
+	incr, err := parseLongDouble(args[3])
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	hash, err := lookupOrCreateHash(key)
+	if err != nil {
+		return err
+	}
+
+	current := "0"
+	if value, exists := hash.Get(field); exists {
+		current = value
+	}
+	base, err := parseLongDouble(current)
+	if err != nil {
+		return errors.New("ERR hash value is not a float")
+	}
+	result, err := addLongDouble(base, incr)
+	if err != nil {
+		return err
+	}
+
+	hash.SetKeepTTL(field, result)
+	signalModifiedKey(key)
+	propagateAs(c, "HSET", key, field, result)
+	if when := hash.ExpireAt(field); when > 0 {
+		propagateAs(c, "HPEXPIREAT", key, strconv.FormatInt(when, 10), "FIELDS", "1", field)
+	}
+	c.Reply.Bulk(result)
+	return nil
+}
@@ -0,0 +1,36 @@
+package commands
+
+func HandleHlen(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	hash, err := lookupHashRead(args[1])
+	if err != nil {
+		return err
+	}
+	if hash == nil {
+		c.Reply.Integer(0)
+		return nil
+	}
+	c.Reply.Integer(int64(hash.Len()))
+	return nil
+}
+
+// HandleHstrlen replies with the length of a field's value, 0 if there is
+// no such field: HSTRLEN key field.
+func HandleHstrlen(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	hash, err := lookupHashRead(args[1])
+	if err != nil {
+		return err
+	}
+	length := 0
+	if hash != nil {
+		value, _ := hash.Get(args[2])
+		length = len(value)
+	}
+	c.Reply.Integer(int64(length))
+	return nil
+}
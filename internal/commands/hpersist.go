@@ -0,0 +1,47 @@
+package commands
+
+// HandleHpersist removes the TTL of fields: HPERSIST key FIELDS numfields
+// field [field ...].
+func HandleHpersist(c *Client, args []string) error {
+	key := args[1]
+	fields, err := parseHashFields(args, 2)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	hash, err := lookupHashWrite(key)
+	if err != nil {
+		return err
+	}
+
+	var persisted []string
+	c.Reply.ArrayLen(len(fields))
+	for _, field := range fields {
+		if hash == nil {
+			c.Reply.Integer(hfeNoField)
+			continue
+		}
+		if _, exists := hash.Get(field); !exists {
+			c.Reply.Integer(hfeNoField)
+			continue
+		}
+		if !hash.Persist(field) {
+			c.Reply.Integer(hfeNoTTL)
+			continue
+		}
+		persisted = append(persisted, field)
+		c.Reply.Integer(hfeSet)
+	}
+
+	if len(persisted) == 0 {
+		preventPropagation(c)
+		return nil
+	}
+	propagateAs(c, hashFieldsCommand("HPERSIST", key, "", persisted)...)
+	updateHashFieldExpires(key, hash)
+	signalModifiedKey(key)
+	return nil
+}
@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+	"github.com/codecrafters-io/redis-starter-go/internal/resp"
+)
+
+// HandleHrandfield replies with random fields of a hash:
+// HRANDFIELD key [count [WITHVALUES]]. A positive count returns distinct
+// fields, at most all of them; a negative one returns exactly -count
+// fields that may repeat.
+func HandleHrandfield(c *Client, args []string) error {
+	if len(args) == 2 {
+		return hrandfieldSingle(c, args[1])
+	}
+
+	count, err := parseInt(args[2])
+	if err != nil {
+		return err
+	}
+	withValues := false
+	if len(args) > 4 || (len(args) == 4 && strings.ToUpper(args[3]) != "WITHVALUES") {
+		return errSyntax
+	}
+	if len(args) == 4 {
+		withValues = true
+		if count < -math.MaxInt64/2 {
+			return errors.New("ERR value is out of range")
+		}
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	hash, err := lookupHashRead(args[1])
+	if err != nil {
+		return err
+	}
+	if hash == nil || count == 0 {
+		c.Reply.ArrayLen(0)
+		return nil
+	}
+
+	var fields, values []string
+	switch {
+	case count < 0:
+		for i := 0; i < -count; i++ {
+			field, value, _ := hash.Random()
+			fields = append(fields, field)
+			values = append(values, value)
+		}
+	case count >= hash.Len():
+		hash.Range(func(field, value string) bool {
+			fields = append(fields, field)
+			values = append(values, value)
+			return true
+		})
+	default:
+		fields, values = hrandfieldDistinct(hash, count)
+	}
+
+	replyHrandfield(c, fields, values, withValues)
+	return nil
+}
+
+func hrandfieldSingle(c *Client, key string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	hash, err := lookupHashRead(key)
+	if err != nil {
+		return err
+	}
+	if hash == nil {
+		c.Reply.Null()
+		return nil
+	}
+	field, _, _ := hash.Random()
+	c.Reply.Bulk(field)
+	return nil
+}
+
+// hrandfieldDistinct picks count distinct fields, count < hash.Len(). When
+// that's most of the hash, it's cheaper to drop random fields from a copy
+// than to keep drawing until enough different ones came up.
+func hrandfieldDistinct(hash *core.Hash, count int) ([]string, []string) {
+	var fields, values []string
+
+	if count*3 > hash.Len() {
+		hash.Range(func(field, value string) bool {
+			fields = append(fields, field)
+			values = append(values, value)
+			return true
+		})
+		for len(fields) > count {
+			i := rand.IntN(len(fields))
+			last := len(fields) - 1
+			fields[i], values[i] = fields[last], values[last]
+			fields, values = fields[:last], values[:last]
+		}
+		return fields, values
+	}
+
+	picked := make(map[string]bool, count)
+	for len(fields) < count {
+		field, value, _ := hash.Random()
+		if picked[field] {
+			continue
+		}
+		picked[field] = true
+		fields = append(fields, field)
+		values = append(values, value)
+	}
+	return fields, values
+}
+
+// replyHrandfield writes the fields, with their values as [field, value]
+// pairs in RESP3 or a flat array in RESP2.
+func replyHrandfield(c *Client, fields, values []string, withValues bool) {
+	if !withValues {
+		c.Reply.BulkArray(fields)
+		return
+	}
+
+	resp3 := c.Reply.Protocol() >= resp.RESP3
+	if resp3 {
+		c.Reply.ArrayLen(len(fields))
+	} else {
+		c.Reply.ArrayLen(len(fields) * 2)
+	}
+	for i := range fields {
+		if resp3 {
+			c.Reply.ArrayLen(2)
+		}
+		c.Reply.Bulk(fields[i])
+		c.Reply.Bulk(values[i])
+	}
+}
@@ -0,0 +1,49 @@
+package commands
+
+// HandleHscan iterates over the fields of a hash: HSCAN key cursor
+// [MATCH pattern] [COUNT count] [NOVALUES]. It replies with field, value
+// pairs, or just the fields with NOVALUES. A hash small enough to be a
+// listpack is returned whole, with cursor 0.
+func HandleHscan(c *Client, args []string) error {
+	cursor, err := parseScanCursor(args[2])
+	if err != nil {
+		return err
+	}
+	opts, err := parseScanOptions(args[3:], "hash")
+	if err != nil {
+		return err
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	hash, err := lookupHashRead(args[1])
+	if err != nil {
+		return err
+	}
+	items := []string{}
+	if hash == nil {
+		replyScan(c, 0, items)
+		return nil
+	}
+
+	found := 0
+	for maxIterations := opts.maxIterations(); ; maxIterations-- {
+		cursor = hash.Scan(cursor, func(field, value string) {
+			if !opts.matches(field) {
+				return
+			}
+			found++
+			items = append(items, field)
+			if !opts.noValues {
+				items = append(items, value)
+			}
+		})
+		if cursor == 0 || maxIterations <= 1 || found >= opts.count {
+			break
+		}
+	}
+
+	replyScan(c, cursor, items)
+	return nil
+}
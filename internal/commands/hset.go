@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HandleHset sets fields of a hash, replying with how many were added:
+// HSET key field value [field value ...].
+func HandleHset(c *Client, args []string) error {
+	added, err := hsetGeneric(args)
+	if err != nil {
+		return err
+	}
+	c.Reply.Integer(int64(added))
+	return nil
+}
+
+// HandleHmset is the older form of HSET, which replies OK.
+func HandleHmset(c *Client, args []string) error {
+	if _, err := hsetGeneric(args); err != nil {
+		return err
+	}
+	c.Reply.OK()
+	return nil
+}
+
+func hsetGeneric(args []string) (int, error) {
+	if len(args)%2 != 0 {
+		return 0, fmt.Errorf("ERR wrong number of arguments for '%s' command", strings.ToLower(args[0]))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	hash, err := lookupOrCreateHash(args[1])
+	if err != nil {
+		return 0, err
+	}
+	added := 0
+	for i := 2; i < len(args); i += 2 {
+		if hash.Set(args[i], args[i+1]) {
+			added++
+		}
+	}
+	updateHashFieldExpires(args[1], hash)
+	signalModifiedKey(args[1])
+	return added, nil
+}
+
+// HandleHsetnx sets a field only if the hash doesn't have it yet:
+// HSETNX key field value.
+func HandleHsetnx(c *Client, args []string) error {
+	key, field, value := args[1], args[2], args[3]
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	hash, err := lookupOrCreateHash(key)
+	if err != nil {
+		return err
+	}
+	if _, exists := hash.Get(field); exists {
+		preventPropagation(c)
+		c.Reply.Integer(0)
+		return nil
+	}
+	hash.Set(field, value)
+	signalModifiedKey(key)
+	c.Reply.Integer(1)
+	return nil
+}
@@ -0,0 +1,64 @@
+package commands
+
+import "time"
+
+func HandleHttl(c *Client, args []string) error {
+	return httlGeneric(c, args, false, false)
+}
+
+func HandleHpttl(c *Client, args []string) error {
+	return httlGeneric(c, args, true, false)
+}
+
+func HandleHexpireTime(c *Client, args []string) error {
+	return httlGeneric(c, args, false, true)
+}
+
+func HandleHpexpireTime(c *Client, args []string) error {
+	return httlGeneric(c, args, true, true)
+}
+
+// httlGeneric replies with the remaining TTL, or the unix deadline, of each
+// field, in seconds (rounded up) or ms: HTTL key FIELDS numfields field
+// [field ...].
+func httlGeneric(c *Client, args []string, outputMs, absolute bool) error {
+	fields, err := parseHashFields(args, 2)
+	if err != nil {
+		return err
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	hash, err := lookupHashRead(args[1])
+	if err != nil {
+		return err
+	}
+
+	var basetime int64
+	if !absolute {
+		basetime = time.Now().UnixMilli()
+	}
+
+	c.Reply.ArrayLen(len(fields))
+	for _, field := range fields {
+		if hash == nil {
+			c.Reply.Integer(hfeNoField)
+			continue
+		}
+		if _, exists := hash.Get(field); !exists {
+			c.Reply.Integer(hfeNoField)
+			continue
+		}
+		when := hash.ExpireAt(field)
+		switch {
+		case when == 0:
+			c.Reply.Integer(hfeNoTTL)
+		case outputMs:
+			c.Reply.Integer(max(when-basetime, 0))
+		default:
+			c.Reply.Integer(max(when+999-basetime, 0) / 1000)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+)
+
+// HyperLogLogs are strings in Redis's own layout (see core.HyperLogLog),
+// so GET, SET and the rest of the string commands work on them too.
+
+var (
+	errNotHLL     = errors.New("WRONGTYPE Key is not a valid HyperLogLog string value.")
+	errCorruptHLL = errors.New("INVALIDOBJ Corrupted HLL object detected")
+)
+
+// lookupHLLRead returns the HyperLogLog at key, nil if there is none. It
+// fails if key holds something else, including a string that isn't one.
+// The caller must hold mu.
+func lookupHLLRead(key string) (core.HyperLogLog, error) {
+	entry, exists := lookupKeyRead(key)
+	return hllFromEntry(entry, exists)
+}
+
+// lookupHLLWrite is lookupHLLRead for commands that modify the
+// HyperLogLog.
+func lookupHLLWrite(key string) (core.HyperLogLog, error) {
+	entry, exists := lookupKeyWrite(key)
+	return hllFromEntry(entry, exists)
+}
+
+func hllFromEntry(entry core.StoreEntry, exists bool) (core.HyperLogLog, error) {
+	value, exists, err := stringFromEntry(entry, exists)
+	if err != nil || !exists {
+		return nil, err
+	}
+	hll, ok := core.ParseHyperLogLog(value)
+	if !ok {
+		return nil, errNotHLL
+	}
+	return hll, nil
+}
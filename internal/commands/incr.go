@@ -1,59 +1,97 @@
 package commands
 
 import (
-	"fmt"
-	"net"
+	"errors"
+	"math"
 	"strconv"
-
-	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
 )
 
-func HandleIncr(conn net.Conn, args []string) {
-	if len(args) < 2 {
-		conn.Write([]byte("-ERR wrong number of arguments for 'INCR' command\r\n"))
-		return
+func HandleIncr(c *Client, args []string) error {
+	return incrDecrGeneric(c, args[1], 1)
+}
+
+func HandleDecr(c *Client, args []string) error {
+	return incrDecrGeneric(c, args[1], -1)
+}
+
+func HandleIncrby(c *Client, args []string) error {
+	incr, ok := parseStrictInt64(args[2])
+	if !ok {
+		return errNotInteger
 	}
+	return incrDecrGeneric(c, args[1], incr)
+}
 
-	key := args[1]
+func HandleDecrby(c *Client, args []string) error {
+	decr, ok := parseStrictInt64(args[2])
+	if !ok {
+		return errNotInteger
+	}
+	if decr == math.MinInt64 {
+		return errors.New("ERR decrement would overflow")
+	}
+	return incrDecrGeneric(c, args[1], -decr)
+}
 
+// incrDecrGeneric adds incr to the integer stored at key, which is created
+// as 0 if it doesn't exist, and replies with the result. The key keeps its
+// TTL.
+func incrDecrGeneric(c *Client, key string, incr int64) error {
 	mu.Lock()
 	defer mu.Unlock()
 
-	entry, exists := store[key]
-	if !exists {
-		store[key] = core.StoreEntry{
-			Data: "1",
-			Type: "string",
+	value, exists, err := lookupStringWrite(key)
+	if err != nil {
+		return err
+	}
+	var current int64
+	if exists {
+		var ok bool
+		if current, ok = parseStrictInt64(value); !ok {
+			return errNotInteger
 		}
-		conn.Write([]byte(":1\r\n"))
-		return
 	}
-
-	if entry.Type != "string" {
-		conn.Write([]byte("-ERR value is not an integer or out of range\r\n"))
-		return
+	if (incr > 0 && current > math.MaxInt64-incr) || (incr < 0 && current < math.MinInt64-incr) {
+		return errors.New("ERR increment or decrement would overflow")
 	}
 
-	strValue, ok := entry.Data.(string)
-	if !ok {
-		conn.Write([]byte("-ERR value is not a string\r\n"))
-		return
-	}
+	current += incr
+	setStringKeepTTL(key, strconv.FormatInt(current, 10))
+	c.Reply.Integer(current)
+	return nil
+}
 
-	intValue, err := strconv.Atoi(strValue)
+// HandleIncrbyfloat is INCRBY for floating point increments:
+// INCRBYFLOAT key increment. It's replicated as a SET of the result, so
+// that replicas don't have to reproduce our float arithmetic.
+func HandleIncrbyfloat(c *Client, args []string) error {
+	key := args[1]
+	incr, err := parseLongDouble(args[2])
 	if err != nil {
-		conn.Write([]byte("-ERR value is not an integer or out of range\r\n"))
-		return
+		return err
 	}
 
-	intValue++
-	newStrValue := strconv.Itoa(intValue)
+	mu.Lock()
+	defer mu.Unlock()
 
-	store[key] = core.StoreEntry{
-		Data:      newStrValue,
-		Type:      "string",
-		ExpiresAt: entry.ExpiresAt,
+	value, exists, err := lookupStringWrite(key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		value = "0"
+	}
+	base, err := parseLongDouble(value)
+	if err != nil {
+		return err
+	}
+	result, err := addLongDouble(base, incr)
+	if err != nil {
+		return err
 	}
 
-	conn.Write([]byte(fmt.Sprintf(":%d\r\n", intValue)))
+	setStringKeepTTL(key, result)
+	propagateAs(c, "SET", key, result, "KEEPTTL")
+	c.Reply.Bulk(result)
+	return nil
 }
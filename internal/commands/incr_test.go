@@ -0,0 +1,40 @@
+package commands
+
+import "testing"
+
+func TestIncrStrictIntegers(t *testing.T) {
+	c := newTestClient()
+	for _, args := range [][]string{
+		{"INCRBY", "incr:strict", "+5"},
+		{"INCRBY", "incr:strict", "05"},
+		{"DECRBY", "incr:strict", "-0"},
+	} {
+		if got := c.do(args...); got != "-ERR value is not an integer or out of range\r\n" {
+			t.Errorf("%v = %q, want an integer error", args, got)
+		}
+	}
+	for _, value := range []string{"+5", "05", "-0"} {
+		c.do("SET", "incr:strict", value)
+		if got := c.do("INCR", "incr:strict"); got != "-ERR value is not an integer or out of range\r\n" {
+			t.Errorf("INCR of %q = %q, want an integer error", value, got)
+		}
+	}
+	c.do("SET", "incr:strict", "-5")
+	if got := c.do("INCRBY", "incr:strict", "10"); got != ":5\r\n" {
+		t.Errorf("INCRBY = %q, want :5", got)
+	}
+}
+
+func TestIncrbyfloatFormatting(t *testing.T) {
+	c := newTestClient()
+	if got := c.do("INCRBYFLOAT", "incr:float", "1e20"); got != "$21\r\n100000000000000000000\r\n" {
+		t.Errorf("INCRBYFLOAT 1e20 = %q", got)
+	}
+	if got := c.do("INCRBYFLOAT", "incr:small", "5.0e-5"); got != "$7\r\n0.00005\r\n" {
+		t.Errorf("INCRBYFLOAT 5.0e-5 = %q", got)
+	}
+	c.do("HSET", "incr:hash", "f", "0")
+	if got := c.do("HINCRBYFLOAT", "incr:hash", "f", "1e20"); got != "$21\r\n100000000000000000000\r\n" {
+		t.Errorf("HINCRBYFLOAT 1e20 = %q", got)
+	}
+}
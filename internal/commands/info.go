@@ -2,18 +2,57 @@ package commands
 
 import (
 	"fmt"
-	"net"
+	"math"
 	"strings"
 
 	"github.com/codecrafters-io/redis-starter-go/internal/utils"
 )
 
-func HandleInfo(conn net.Conn, args []string) {
-	if len(args) < 2 || strings.ToLower(args[1]) != "replication" {
-		conn.Write([]byte("-ERR unsupported INFO section\r\n"))
-		return
+// infoSections lists the INFO sections in the order they're printed.
+var infoSections = []struct {
+	name  string
+	build func(sb *strings.Builder)
+}{
+	{"stats", infoStats},
+	{"replication", infoReplication},
+	{"keyspace", infoKeyspace},
+}
+
+// HandleInfo prints the requested sections, or all of them when none (or
+// "all", "default" or "everything") is given. Unknown sections are skipped.
+func HandleInfo(c *Client, args []string) error {
+	wanted := make(map[string]bool)
+	for _, arg := range args[1:] {
+		wanted[strings.ToLower(arg)] = true
+	}
+	all := len(wanted) == 0 || wanted["all"] || wanted["default"] || wanted["everything"]
+
+	var sb strings.Builder
+	for _, section := range infoSections {
+		if !all && !wanted[section.name] {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\r\n")
+		}
+		fmt.Fprintf(&sb, "# %s\r\n", strings.ToUpper(section.name[:1])+section.name[1:])
+		section.build(&sb)
 	}
 
+	c.Reply.Verbatim("txt", sb.String())
+	return nil
+}
+
+func infoStats(sb *strings.Builder) {
+	stale := math.Float64frombits(expireStats.stalePerc.Load())
+	fmt.Fprintf(sb, "expired_keys:%d\r\n", expireStats.expiredKeys.Load())
+	fmt.Fprintf(sb, "expired_subkeys:%d\r\n", expireStats.expiredSubkeys.Load())
+	fmt.Fprintf(sb, "expired_stale_perc:%.2f\r\n", stale*100)
+	fmt.Fprintf(sb, "expired_time_cap_reached_count:%d\r\n", expireStats.timeCapReachedCount.Load())
+	fmt.Fprintf(sb, "expire_cycle_cpu_milliseconds:%d\r\n", expireStats.cycleCPUMicroseconds.Load()/1000)
+}
+
+func infoReplication(sb *strings.Builder) {
 	role, _ := GetConfig("role")
 
 	masterReplID, exists := GetConfig("master_replid")
@@ -26,8 +65,15 @@ func HandleInfo(conn net.Conn, args []string) {
 		masterReplOffset = "0"
 	}
 
-	infoResponse := fmt.Sprintf("role:%s\r\nmaster_replid:%s\r\nmaster_repl_offset:%s", role, masterReplID, masterReplOffset)
-	response := fmt.Sprintf("$%d\r\n%s\r\n", len(infoResponse), infoResponse)
+	fmt.Fprintf(sb, "role:%s\r\nmaster_replid:%s\r\nmaster_repl_offset:%s\r\n", role, masterReplID, masterReplOffset)
+}
 
-	conn.Write([]byte(response))
+func infoKeyspace(sb *strings.Builder) {
+	mu.RLock()
+	keys, withTTL, withFieldTTL := store.Len(), len(expires), len(hashFieldExpires)
+	mu.RUnlock()
+
+	if keys > 0 {
+		fmt.Fprintf(sb, "db0:keys=%d,expires=%d,avg_ttl=0,subexpiry=%d\r\n", keys, withTTL, withFieldTTL)
+	}
 }
@@ -1,32 +1,30 @@
 package commands
 
 import (
-	"fmt"
-	"net"
-)
-
-func HandleKeys(conn net.Conn, args []string) {
-	mu.RLock()
-	defer mu.RUnlock()
+	"time"
 
-	keysList := make([]string, 0, len(store)) // Preallocating the slice for better performance
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+	"github.com/codecrafters-io/redis-starter-go/internal/utils"
+)
 
-	for key := range store {
-		keysList = append(keysList, key)
-	}
+func HandleKeys(c *Client, args []string) error {
+	pattern := args[1]
+	allKeys := pattern == "*"
 
-	// RESP format: "*<count>\r\n$<length>\r\n<key>\r\n"
-	resp := fmt.Sprintf("*%d\r\n", len(keysList))
-	for _, key := range keysList {
-		resp += fmt.Sprintf("$%d\r\n%s\r\n", len(key), key)
-	}
+	mu.RLock()
+	keysList := make([]string, 0, store.Len()) // Preallocating the slice for better performance
+	now := time.Now().UnixMilli()
+	store.Range(func(key string, entry core.StoreEntry) bool {
+		if isExpired(entry, now) {
+			return true // left for the active expire cycle
+		}
+		if allKeys || utils.GlobMatch(pattern, key, false) {
+			keysList = append(keysList, key)
+		}
+		return true
+	})
+	mu.RUnlock()
 
-	conn.Write([]byte(resp))
+	c.Reply.BulkArray(keysList)
+	return nil
 }
-
-/* TODO:
-1. Here we have to impl. that when getting the key if the key has
-expired we have to delete that particular key as well and return the
-remaining (similar to what we did in the GET), besides this also impl
-the CRON job to delete the expired keys
-*/
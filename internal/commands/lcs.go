@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser"
+)
+
+// lcsMatch is a run of the longest common subsequence found in both
+// strings, as the offsets of its first and last bytes in each of them.
+type lcsMatch struct {
+	aStart, aEnd int
+	bStart, bEnd int
+}
+
+// HandleLcs replies with the longest common subsequence of the strings at
+// key1 and key2: LCS key1 key2 [LEN] [IDX] [MINMATCHLEN len]
+// [WITHMATCHLEN]. LEN replies with its length instead, IDX with where its
+// runs are in each string (the last one first) and its length; runs
+// shorter than MINMATCHLEN are left out, and WITHMATCHLEN adds the length
+// of each.
+func HandleLcs(c *Client, args []string) error {
+	getLen, getIdx, withMatchLen := false, false, false
+	minMatchLen := 0
+	for i := 3; i < len(args); i++ {
+		switch opt := strings.ToUpper(args[i]); {
+		case opt == "LEN":
+			getLen = true
+		case opt == "IDX":
+			getIdx = true
+		case opt == "WITHMATCHLEN":
+			withMatchLen = true
+		case opt == "MINMATCHLEN" && i+1 < len(args):
+			i++
+			n, err := parseInt(args[i])
+			if err != nil {
+				return err
+			}
+			minMatchLen = max(n, 0)
+		default:
+			return errSyntax
+		}
+	}
+	if getLen && getIdx {
+		return errors.New("ERR If you want both the length and indexes, please just use IDX.")
+	}
+
+	mu.RLock()
+	a, _, errA := lookupStringRead(args[1])
+	b, _, errB := lookupStringRead(args[2])
+	mu.RUnlock()
+	if errA != nil || errB != nil {
+		return errors.New("ERR The specified keys must contain string values")
+	}
+
+	// the table of LCS lengths of every pair of prefixes
+	if int64(len(a)+1)*int64(len(b)+1)*4 > parser.MaxBulkLen() {
+		return errors.New("ERR Insufficient memory, transient memory for LCS exceeds proto-max-bulk-len")
+	}
+	width := len(b) + 1
+	table := make([]uint32, (len(a)+1)*width)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				table[i*width+j] = table[(i-1)*width+j-1] + 1
+			} else {
+				table[i*width+j] = max(table[(i-1)*width+j], table[i*width+j-1])
+			}
+		}
+	}
+	length := int(table[len(a)*width+len(b)])
+
+	if getLen {
+		c.Reply.Integer(int64(length))
+		return nil
+	}
+
+	// walk the table back from the end to spell the LCS out, collecting
+	// its runs of contiguous bytes on the way
+	lcs := make([]byte, length)
+	var matches []lcsMatch
+	idx := length
+	current := lcsMatch{aStart: -1}
+	for i, j := len(a), len(b); i > 0 && j > 0; {
+		emit := false
+		if a[i-1] == b[j-1] {
+			lcs[idx-1] = a[i-1]
+			switch {
+			case current.aStart < 0:
+				current = lcsMatch{i - 1, i - 1, j - 1, j - 1}
+			case current.aStart == i && current.bStart == j:
+				current.aStart--
+				current.bStart--
+			default:
+				emit = true
+			}
+			if current.aStart == 0 || current.bStart == 0 {
+				emit = true
+			}
+			idx--
+			i--
+			j--
+		} else {
+			if table[(i-1)*width+j] > table[i*width+j-1] {
+				i--
+			} else {
+				j--
+			}
+			emit = current.aStart >= 0
+		}
+
+		if emit {
+			if current.aEnd-current.aStart+1 >= minMatchLen {
+				matches = append(matches, current)
+			}
+			current = lcsMatch{aStart: -1}
+		}
+	}
+
+	if !getIdx {
+		c.Reply.Bulk(string(lcs))
+		return nil
+	}
+	c.Reply.MapLen(2)
+	c.Reply.Bulk("matches")
+	c.Reply.ArrayLen(len(matches))
+	for _, m := range matches {
+		if withMatchLen {
+			c.Reply.ArrayLen(3)
+		} else {
+			c.Reply.ArrayLen(2)
+		}
+		c.Reply.ArrayLen(2)
+		c.Reply.Integer(int64(m.aStart))
+		c.Reply.Integer(int64(m.aEnd))
+		c.Reply.ArrayLen(2)
+		c.Reply.Integer(int64(m.bStart))
+		c.Reply.Integer(int64(m.bEnd))
+		if withMatchLen {
+			c.Reply.Integer(int64(m.aEnd - m.aStart + 1))
+		}
+	}
+	c.Reply.Bulk("len")
+	c.Reply.Integer(int64(length))
+	return nil
+}
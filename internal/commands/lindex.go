@@ -0,0 +1,29 @@
+package commands
+
+func HandleLindex(c *Client, args []string) error {
+	key := args[1]
+	index, err := parseInt(args[2])
+	if err != nil {
+		return err
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	list, err := lookupListRead(key)
+	if err != nil {
+		return err
+	}
+	if list == nil {
+		c.Reply.Null()
+		return nil
+	}
+
+	value, ok := list.Index(index)
+	if !ok {
+		c.Reply.Null()
+		return nil
+	}
+	c.Reply.Bulk(value)
+	return nil
+}
@@ -0,0 +1,54 @@
+package commands
+
+import "strings"
+
+// HandleLinsert inserts an element next to the first occurrence of a
+// pivot: LINSERT key BEFORE|AFTER pivot element. It replies with the new
+// length, -1 if the pivot wasn't found and 0 if the key doesn't exist.
+func HandleLinsert(c *Client, args []string) error {
+	key, pivot, value := args[1], args[3], args[4]
+
+	var after bool
+	switch strings.ToUpper(args[2]) {
+	case "BEFORE":
+	case "AFTER":
+		after = true
+	default:
+		return errSyntax
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	list, err := lookupListWrite(key)
+	if err != nil {
+		return err
+	}
+	if list == nil {
+		preventPropagation(c)
+		c.Reply.Integer(0)
+		return nil
+	}
+
+	at := -1
+	list.Iterate(false, func(i int, v string) bool {
+		if v == pivot {
+			at = i
+			return false
+		}
+		return true
+	})
+	if at == -1 {
+		preventPropagation(c)
+		c.Reply.Integer(-1)
+		return nil
+	}
+
+	if after {
+		at++
+	}
+	list.Insert(at, value)
+	signalModifiedKey(key)
+	c.Reply.Integer(int64(list.Len()))
+	return nil
+}
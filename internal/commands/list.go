@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+)
+
+// Helpers shared by the list commands. Like the other keyspace helpers
+// they expect the caller to hold mu.
+
+// lookupListRead returns the list at key, nil if there is none.
+func lookupListRead(key string) (*core.Quicklist, error) {
+	entry, exists := lookupKeyRead(key)
+	return listFromEntry(entry, exists)
+}
+
+// lookupListWrite is lookupListRead for commands that modify the list.
+func lookupListWrite(key string) (*core.Quicklist, error) {
+	entry, exists := lookupKeyWrite(key)
+	return listFromEntry(entry, exists)
+}
+
+func listFromEntry(entry core.StoreEntry, exists bool) (*core.Quicklist, error) {
+	if !exists {
+		return nil, nil
+	}
+	if entry.Type != "list" {
+		return nil, errWrongType
+	}
+	return entry.Data.(*core.Quicklist), nil
+}
+
+// createList stores a new empty list at key.
+func createList(key string) *core.Quicklist {
+	list := core.NewQuicklist()
+	setKey(key, core.StoreEntry{Type: "list", Data: list})
+	return list
+}
+
+// deleteListIfEmpty removes key once its list has no elements left, as an
+// empty list is never stored.
+func deleteListIfEmpty(key string, list *core.Quicklist) {
+	if list.Len() == 0 {
+		deleteKey(key)
+	}
+}
+
+// listPush pushes values to the head (where == "left") or tail of list.
+func listPush(list *core.Quicklist, where string, values ...string) {
+	for _, value := range values {
+		if where == "left" {
+			list.PushHead(value)
+		} else {
+			list.PushTail(value)
+		}
+	}
+}
+
+func listPop(list *core.Quicklist, where string) (string, bool) {
+	if where == "left" {
+		return list.PopHead()
+	}
+	return list.PopTail()
+}
+
+// parseWhere parses a LEFT/RIGHT argument into "left" or "right".
+func parseWhere(arg string) (string, error) {
+	switch strings.ToLower(arg) {
+	case "left":
+		return "left", nil
+	case "right":
+		return "right", nil
+	}
+	return "", errSyntax
+}
+
+// normalizeRange clamps the inclusive range start..stop (negative indexes
+// count from the end) to a list of length n. ok is false if it's empty.
+func normalizeRange(start, stop, n int) (int, int, bool) {
+	if start < 0 {
+		start += n
+	}
+	if stop < 0 {
+		stop += n
+	}
+	if start < 0 {
+		start = 0
+	}
+	if start > stop || start >= n {
+		return 0, 0, false
+	}
+	if stop >= n {
+		stop = n - 1
+	}
+	return start, stop, true
+}
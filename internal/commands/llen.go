@@ -0,0 +1,18 @@
+package commands
+
+func HandleLlen(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	list, err := lookupListRead(args[1])
+	if err != nil {
+		return err
+	}
+	if list == nil {
+		c.Reply.Integer(0)
+		return nil
+	}
+
+	c.Reply.Integer(int64(list.Len()))
+	return nil
+}
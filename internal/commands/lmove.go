@@ -0,0 +1,63 @@
+package commands
+
+// HandleLmove pops an element from one end of a list and pushes it to an
+// end of another (or the same) list:
+// LMOVE source destination LEFT|RIGHT LEFT|RIGHT.
+func HandleLmove(c *Client, args []string) error {
+	from, err := parseWhere(args[3])
+	if err != nil {
+		return err
+	}
+	to, err := parseWhere(args[4])
+	if err != nil {
+		return err
+	}
+	return moveGeneric(c, args[1], args[2], from, to)
+}
+
+// HandleRpoplpush is LMOVE source destination RIGHT LEFT.
+func HandleRpoplpush(c *Client, args []string) error {
+	return moveGeneric(c, args[1], args[2], "right", "left")
+}
+
+func moveGeneric(c *Client, src, dst, from, to string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	value, ok, err := listMove(src, dst, from, to)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		preventPropagation(c)
+		c.Reply.Null()
+		return nil
+	}
+
+	c.Reply.Bulk(value)
+	return nil
+}
+
+// listMove does the work of LMOVE, reporting false if src doesn't exist.
+// The destination's type is checked before anything is popped.
+func listMove(src, dst, from, to string) (string, bool, error) {
+	srcList, err := lookupListWrite(src)
+	if err != nil || srcList == nil {
+		return "", false, err
+	}
+	dstList, err := lookupListWrite(dst)
+	if err != nil {
+		return "", false, err
+	}
+
+	value, _ := listPop(srcList, from)
+	if dstList == nil {
+		dstList = createList(dst)
+	}
+	listPush(dstList, to, value)
+	signalModifiedKey(src)
+	signalModifiedKey(dst)
+	signalKeyAsReady(dst)
+	deleteListIfEmpty(src, srcList)
+	return value, true, nil
+}
@@ -0,0 +1,186 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+)
+
+func HandleLpop(c *Client, args []string) error {
+	return popGeneric(c, args, "left")
+}
+
+func HandleRpop(c *Client, args []string) error {
+	return popGeneric(c, args, "right")
+}
+
+// popGeneric implements LPOP/RPOP key [count]. Without a count it replies
+// with a single element, with one an array of up to count elements.
+func popGeneric(c *Client, args []string, where string) error {
+	if len(args) > 3 {
+		return fmt.Errorf("ERR wrong number of arguments for '%s' command", strings.ToLower(args[0]))
+	}
+	key := args[1]
+
+	hasCount := len(args) == 3
+	count := 1
+	if hasCount {
+		n, err := strconv.Atoi(args[2])
+		if err != nil || n < 0 {
+			return errors.New("ERR value is out of range, must be positive")
+		}
+		count = n
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	list, err := lookupListWrite(key)
+	if err != nil {
+		return err
+	}
+	if list == nil {
+		preventPropagation(c)
+		if hasCount {
+			c.Reply.NullArray()
+		} else {
+			c.Reply.Null()
+		}
+		return nil
+	}
+
+	if !hasCount {
+		value, _ := listPop(list, where)
+		signalModifiedKey(key)
+		deleteListIfEmpty(key, list)
+		c.Reply.Bulk(value)
+		return nil
+	}
+
+	if count == 0 {
+		preventPropagation(c)
+	}
+	c.Reply.BulkArray(listPopN(key, list, where, count))
+	return nil
+}
+
+// listPopN pops up to count elements, deleting key if that empties it.
+func listPopN(key string, list *core.Quicklist, where string, count int) []string {
+	values := make([]string, 0, min(count, list.Len()))
+	for len(values) < count {
+		value, ok := listPop(list, where)
+		if !ok {
+			break
+		}
+		values = append(values, value)
+	}
+	if len(values) > 0 {
+		signalModifiedKey(key)
+	}
+	deleteListIfEmpty(key, list)
+	return values
+}
+
+// HandleLmpop pops from the first non-empty list among the given keys:
+// LMPOP numkeys key [key ...] LEFT|RIGHT [COUNT count].
+func HandleLmpop(c *Client, args []string) error {
+	keys, where, count, err := parseMpopArgs(args, 1)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	served, err := listMpop(c, keys, where, count)
+	if err != nil {
+		return err
+	}
+	if !served {
+		preventPropagation(c)
+		c.Reply.NullArray()
+	}
+	return nil
+}
+
+// listMpop pops up to count elements from the first non-empty list among
+// keys and replies with [key, elements], reporting false if all of them
+// are empty.
+func listMpop(c *Client, keys []string, where string, count int) (bool, error) {
+	for _, key := range keys {
+		list, err := lookupListWrite(key)
+		if err != nil {
+			return false, err
+		}
+		if list == nil {
+			continue
+		}
+
+		values := listPopN(key, list, where, count)
+		// replicated as a plain pop from the list it picked
+		propagateAs(c, strings.ToUpper(where[:1])+"POP", key, strconv.Itoa(len(values)))
+		c.Reply.ArrayLen(2)
+		c.Reply.Bulk(key)
+		c.Reply.BulkArray(values)
+		return true, nil
+	}
+	return false, nil
+}
+
+// parseMpopArgs parses "numkeys key [key ...] LEFT|RIGHT [COUNT count]"
+// starting at args[numkeysIdx].
+func parseMpopArgs(args []string, numkeysIdx int) ([]string, string, int, error) {
+	numkeys, err := strconv.Atoi(args[numkeysIdx])
+	if err != nil || numkeys <= 0 {
+		return nil, "", 0, errors.New("ERR numkeys should be greater than 0")
+	}
+	// room for the keys and LEFT|RIGHT, checked before adding numkeys to
+	// anything
+	if numkeys > len(args)-numkeysIdx-2 {
+		return nil, "", 0, errSyntax
+	}
+	whereIdx := numkeysIdx + 1 + numkeys
+	where, err := parseWhere(args[whereIdx])
+	if err != nil {
+		return nil, "", 0, err
+	}
+
+	count, countSet := 1, false
+	for i := whereIdx + 1; i < len(args); i++ {
+		if !strings.EqualFold(args[i], "COUNT") || countSet || i+1 == len(args) {
+			return nil, "", 0, errSyntax
+		}
+		i++
+		count, err = strconv.Atoi(args[i])
+		if err != nil || count <= 0 {
+			return nil, "", 0, errors.New("ERR count should be greater than 0")
+		}
+		countSet = true
+	}
+	return args[numkeysIdx+1 : whereIdx], where, count, nil
+}
+
+// lmpopKeys finds the keys of LMPOP for COMMAND GETKEYS.
+func lmpopKeys(args []string) []int {
+	return numkeysKeys(args, 1)
+}
+
+// numkeysKeys returns the positions of the keys following a numkeys
+// argument at args[numkeysIdx].
+func numkeysKeys(args []string, numkeysIdx int) []int {
+	if numkeysIdx >= len(args) {
+		return nil
+	}
+	numkeys, err := strconv.Atoi(args[numkeysIdx])
+	if err != nil || numkeys <= 0 || numkeys > len(args)-numkeysIdx-1 {
+		return nil
+	}
+	keys := make([]int, numkeys)
+	for i := range keys {
+		keys[i] = numkeysIdx + 1 + i
+	}
+	return keys
+}
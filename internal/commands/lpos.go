@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+)
+
+// HandleLpos finds the index of matching elements:
+// LPOS key element [RANK rank] [COUNT num-matches] [MAXLEN len].
+// RANK picks the n-th match (negative ranks search from the tail), COUNT
+// returns several matches (0 for all of them) and MAXLEN limits how many
+// elements are compared.
+func HandleLpos(c *Client, args []string) error {
+	key, value := args[1], args[2]
+
+	rank, count, maxlen := 1, -1, 0
+	for i := 3; i < len(args); i += 2 {
+		if i+1 == len(args) {
+			return errSyntax
+		}
+		n, err := parseInt(args[i+1])
+		if err != nil {
+			return err
+		}
+		switch strings.ToUpper(args[i]) {
+		case "RANK":
+			if n == 0 {
+				return errors.New("ERR RANK can't be zero: use 1 to start from the first match, 2 from the second ... or use negative to start from the end of the list")
+			}
+			rank = n
+		case "COUNT":
+			if n < 0 {
+				return errors.New("ERR COUNT can't be negative")
+			}
+			count = n
+		case "MAXLEN":
+			if n < 0 {
+				return errors.New("ERR MAXLEN can't be negative")
+			}
+			maxlen = n
+		default:
+			return errSyntax
+		}
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	list, err := lookupListRead(key)
+	if err != nil {
+		return err
+	}
+
+	var matches []int64
+	if list != nil {
+		skip := rank - 1
+		if rank < 0 {
+			skip = -rank - 1
+		}
+		compared := 0
+		list.Iterate(rank < 0, func(i int, v string) bool {
+			if maxlen > 0 && compared == maxlen {
+				return false
+			}
+			compared++
+			if v != value {
+				return true
+			}
+			if skip > 0 {
+				skip--
+				return true
+			}
+			matches = append(matches, int64(i))
+			return count == 0 || len(matches) < max(count, 1)
+		})
+	}
+
+	if count == -1 {
+		if len(matches) == 0 {
+			c.Reply.Null()
+		} else {
+			c.Reply.Integer(matches[0])
+		}
+		return nil
+	}
+
+	c.Reply.ArrayLen(len(matches))
+	for _, i := range matches {
+		c.Reply.Integer(i)
+	}
+	return nil
+}
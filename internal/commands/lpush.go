@@ -0,0 +1,46 @@
+package commands
+
+func HandleLpush(c *Client, args []string) error {
+	return pushGeneric(c, args, "left", false)
+}
+
+func HandleRpush(c *Client, args []string) error {
+	return pushGeneric(c, args, "right", false)
+}
+
+func HandleLpushx(c *Client, args []string) error {
+	return pushGeneric(c, args, "left", true)
+}
+
+func HandleRpushx(c *Client, args []string) error {
+	return pushGeneric(c, args, "right", true)
+}
+
+// pushGeneric pushes the elements in args[2:] one after the other, so
+// LPUSH ends up with them in reverse order. With onlyExisting (the X
+// variants) a missing key is left alone.
+func pushGeneric(c *Client, args []string, where string, onlyExisting bool) error {
+	key := args[1]
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	list, err := lookupListWrite(key)
+	if err != nil {
+		return err
+	}
+	if list == nil {
+		if onlyExisting {
+			preventPropagation(c)
+			c.Reply.Integer(0)
+			return nil
+		}
+		list = createList(key)
+	}
+
+	listPush(list, where, args[2:]...)
+	signalModifiedKey(key)
+	signalKeyAsReady(key)
+	c.Reply.Integer(int64(list.Len()))
+	return nil
+}
@@ -0,0 +1,38 @@
+package commands
+
+func HandleLrange(c *Client, args []string) error {
+	key := args[1]
+	start, err := parseInt(args[2])
+	if err != nil {
+		return err
+	}
+	stop, err := parseInt(args[3])
+	if err != nil {
+		return err
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	list, err := lookupListRead(key)
+	if err != nil {
+		return err
+	}
+	if list == nil {
+		c.Reply.ArrayLen(0)
+		return nil
+	}
+
+	start, stop, ok := normalizeRange(start, stop, list.Len())
+	if !ok {
+		c.Reply.ArrayLen(0)
+		return nil
+	}
+
+	c.Reply.ArrayLen(stop - start + 1)
+	list.Range(start, stop, func(value string) bool {
+		c.Reply.Bulk(value)
+		return true
+	})
+	return nil
+}
@@ -0,0 +1,40 @@
+package commands
+
+// HandleLrem removes occurrences of an element: LREM key count element.
+// count > 0 removes the first count from the head, count < 0 the first
+// -count from the tail, and 0 all of them.
+func HandleLrem(c *Client, args []string) error {
+	key, value := args[1], args[3]
+	count, err := parseInt(args[2])
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	list, err := lookupListWrite(key)
+	if err != nil {
+		return err
+	}
+	if list == nil {
+		preventPropagation(c)
+		c.Reply.Integer(0)
+		return nil
+	}
+
+	limit := count
+	if limit < 0 {
+		limit = -limit
+	}
+	removed := list.RemoveIf(count < 0, limit, func(v string) bool { return v == value })
+	deleteListIfEmpty(key, list)
+
+	if removed == 0 {
+		preventPropagation(c)
+	} else {
+		signalModifiedKey(key)
+	}
+	c.Reply.Integer(int64(removed))
+	return nil
+}
@@ -0,0 +1,29 @@
+package commands
+
+import "errors"
+
+func HandleLset(c *Client, args []string) error {
+	key := args[1]
+	index, err := parseInt(args[2])
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	list, err := lookupListWrite(key)
+	if err != nil {
+		return err
+	}
+	if list == nil {
+		return errors.New("ERR no such key")
+	}
+	if !list.Set(index, args[3]) {
+		return errors.New("ERR index out of range")
+	}
+	signalModifiedKey(key)
+
+	c.Reply.OK()
+	return nil
+}
@@ -0,0 +1,41 @@
+package commands
+
+// HandleLtrim keeps only the elements from start to stop inclusive.
+func HandleLtrim(c *Client, args []string) error {
+	key := args[1]
+	start, err := parseInt(args[2])
+	if err != nil {
+		return err
+	}
+	stop, err := parseInt(args[3])
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	list, err := lookupListWrite(key)
+	if err != nil {
+		return err
+	}
+	if list == nil {
+		preventPropagation(c)
+		c.Reply.OK()
+		return nil
+	}
+
+	n := list.Len()
+	start, stop, ok := normalizeRange(start, stop, n)
+	if !ok {
+		list.DeleteRange(0, n)
+	} else {
+		list.DeleteRange(stop+1, n-stop-1)
+		list.DeleteRange(0, start)
+	}
+	signalModifiedKey(key)
+	deleteListIfEmpty(key, list)
+
+	c.Reply.OK()
+	return nil
+}
@@ -0,0 +1,19 @@
+package commands
+
+// HandleMget replies with the value of each key, nil for those that don't
+// hold a string: MGET key [key ...].
+func HandleMget(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	c.Reply.ArrayLen(len(args) - 1)
+	for _, key := range args[1:] {
+		value, exists, err := lookupStringRead(key)
+		if err != nil || !exists {
+			c.Reply.Null()
+			continue
+		}
+		c.Reply.Bulk(value)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+)
+
+// HandleMset sets several keys at once, like SET would each of them:
+// MSET key value [key value ...].
+func HandleMset(c *Client, args []string) error {
+	if len(args)%2 == 0 {
+		return fmt.Errorf("ERR wrong number of arguments for '%s' command", strings.ToLower(args[0]))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for i := 1; i < len(args); i += 2 {
+		setKey(args[i], core.StoreEntry{Type: "string", Data: args[i+1]})
+	}
+	c.Reply.OK()
+	return nil
+}
+
+// HandleMsetnx is MSET, but sets nothing if any of the keys exists. It
+// replies with 1 if the keys were set, 0 otherwise: MSETNX key value
+// [key value ...].
+func HandleMsetnx(c *Client, args []string) error {
+	if len(args)%2 == 0 {
+		return fmt.Errorf("ERR wrong number of arguments for '%s' command", strings.ToLower(args[0]))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for i := 1; i < len(args); i += 2 {
+		if _, exists := lookupKeyWrite(args[i]); exists {
+			preventPropagation(c)
+			c.Reply.Integer(0)
+			return nil
+		}
+	}
+	for i := 1; i < len(args); i += 2 {
+		setKey(args[i], core.StoreEntry{Type: "string", Data: args[i+1]})
+	}
+	c.Reply.Integer(1)
+	return nil
+}
@@ -1,23 +1,22 @@
 package commands
 
 import (
-	"net"
+	"errors"
 
 	models "github.com/codecrafters-io/redis-starter-go/internal/models/core"
 )
 
-func HandleMulti(conn net.Conn) {
+func HandleMulti(c *Client, args []string) error {
 	models.ClientMu.Lock()
 	defer models.ClientMu.Unlock()
 
-	state, exists := models.ClientStates[conn]
-	if !exists {
-		state = &models.ClientState{InTransaction: true}
-		models.ClientStates[conn] = state
-	} else {
-		state.InTransaction = true
-		state.CommandQueue = make([][]string, 0)
+	if c.InTransaction {
+		return errors.New("ERR MULTI calls can not be nested")
 	}
+	c.InTransaction = true
+	c.CommandQueue = make([][]string, 0)
+	c.DirtyExec = false
 
-	conn.Write([]byte("+OK\r\n"))
+	c.Reply.OK()
+	return nil
 }
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+func parseInt(arg string) (int, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, errNotInteger
+	}
+	return n, nil
+}
+
+// parseStrictInt64 parses an integer the way Redis's string2ll does,
+// which is stricter than strconv: no '+' sign, no leading zeros and no
+// "-0".
+func parseStrictInt64(s string) (int64, bool) {
+	digits := strings.TrimPrefix(s, "-")
+	if digits == "" || digits[0] < '0' || digits[0] > '9' || (digits[0] == '0' && s != "0") {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	return n, err == nil
+}
+
+// parseFloat parses a double argument; NaN isn't one.
+func parseFloat(arg string) (float64, error) {
+	f, err := strconv.ParseFloat(arg, 64)
+	if err != nil || math.IsNaN(f) {
+		return 0, errors.New("ERR value is not a valid float")
+	}
+	return f, nil
+}
+
+// Float increments (INCRBYFLOAT, HINCRBYFLOAT) are computed like Redis
+// does, in a C long double: 64 bits of mantissa rather than a float64's
+// 53, so that e.g. 10.1 + 0.1 gives 10.2 and not 10.199999999999999.
+const (
+	longDoublePrec   = 64
+	longDoubleMaxExp = 16384
+)
+
+// parseLongDouble parses a number for a float increment.
+func parseLongDouble(arg string) (*big.Float, error) {
+	f, _, err := big.ParseFloat(arg, 10, longDoublePrec, big.ToNearestEven)
+	if err != nil || f.MantExp(nil) > longDoubleMaxExp {
+		return nil, errors.New("ERR value is not a valid float")
+	}
+	return f, nil
+}
+
+// addLongDouble adds two long doubles and formats the sum the way Redis
+// stores it: printf's %.17Lf, with trailing zeros and a trailing dot
+// trimmed, so never in exponent notation.
+func addLongDouble(a, b *big.Float) (string, error) {
+	if a.IsInf() || b.IsInf() {
+		return "", errors.New("ERR increment would produce NaN or Infinity")
+	}
+	sum := new(big.Float).SetPrec(longDoublePrec).Add(a, b)
+	if sum.MantExp(nil) > longDoubleMaxExp {
+		return "", errors.New("ERR increment would produce NaN or Infinity")
+	}
+
+	text := strings.TrimRight(sum.Text('f', 17), "0")
+	text = strings.TrimSuffix(text, ".")
+	if text == "-0" {
+		return "0", nil
+	}
+	return text, nil
+}
@@ -0,0 +1,47 @@
+package commands
+
+import "testing"
+
+func TestAddLongDouble(t *testing.T) {
+	tests := []struct {
+		a, b, want string
+	}{
+		{"10.50", "0.1", "10.6"},
+		{"5.0e3", "2.0e2", "5200"},
+		{"0", "1e20", "100000000000000000000"},
+		{"0", "1.5e25", "15000000000000000000000000"},
+		{"0", "5.0e-5", "0.00005"},
+		{"0", "1.25e-15", "0.00000000000000125"},
+		{"0", "1e-20", "0"},
+		{"0", "-1e-20", "0"},
+		{"1", "-1", "0"},
+		{"-3", "0.5", "-2.5"},
+	}
+	for _, tt := range tests {
+		a, err := parseLongDouble(tt.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := parseLongDouble(tt.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := addLongDouble(a, b)
+		if err != nil || got != tt.want {
+			t.Errorf("%s + %s = %q, %v, want %q", tt.a, tt.b, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseStrictInt64(t *testing.T) {
+	for _, s := range []string{"0", "5", "-5", "9223372036854775807", "-9223372036854775808"} {
+		if _, ok := parseStrictInt64(s); !ok {
+			t.Errorf("parseStrictInt64(%q) failed", s)
+		}
+	}
+	for _, s := range []string{"", "-", "+5", "05", "-0", "-05", " 5", "5 ", "1e3", "9223372036854775808"} {
+		if n, ok := parseStrictInt64(s); ok {
+			t.Errorf("parseStrictInt64(%q) = %d, want an error", s, n)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package commands
+
+func HandlePersist(c *Client, args []string) error {
+	key := args[1]
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	entry, exists := lookupKeyWrite(key)
+	if !exists || entry.ExpiresAt == 0 {
+		preventPropagation(c)
+		c.Reply.Integer(0)
+		return nil
+	}
+
+	setExpire(key, 0)
+	c.Reply.Integer(1)
+	return nil
+}
@@ -0,0 +1,41 @@
+package commands
+
+import "github.com/codecrafters-io/redis-starter-go/internal/models/core"
+
+// HandlePfadd adds elements to the HyperLogLog at key, creating it if
+// needed: PFADD key [element ...]. It replies 1 if the estimated
+// cardinality may have changed, that is if a register was raised or the
+// key was created, and 0 otherwise.
+func HandlePfadd(c *Client, args []string) error {
+	key := args[1]
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	hll, err := lookupHLLWrite(key)
+	if err != nil {
+		return err
+	}
+	updated := false
+	if hll == nil {
+		hll = core.NewHyperLogLog()
+		updated = true
+	}
+	for _, element := range args[2:] {
+		changed, ok := hll.Add(element)
+		if !ok {
+			return errCorruptHLL
+		}
+		updated = updated || changed
+	}
+
+	if !updated {
+		preventPropagation(c)
+		c.Reply.Integer(0)
+		return nil
+	}
+	hll.InvalidateCache()
+	setStringKeepTTL(key, string(hll))
+	c.Reply.Integer(1)
+	return nil
+}
@@ -0,0 +1,51 @@
+package commands
+
+import "github.com/codecrafters-io/redis-starter-go/internal/models/core"
+
+// HandlePfcount replies with the estimated cardinality of the HyperLogLog
+// at key, 0 if it doesn't exist: PFCOUNT key [key ...]. With several keys
+// it's the cardinality of their union, computed on a temporary merge.
+//
+// With a single key the estimate is cached in the value, and that change
+// is replicated so that replicas keep the same bytes.
+func HandlePfcount(c *Client, args []string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(args) > 2 {
+		merged := make([]uint8, core.HLLRegisters)
+		for _, key := range args[1:] {
+			hll, err := lookupHLLRead(key)
+			if err != nil {
+				return err
+			}
+			if hll != nil && !hll.Merge(merged) {
+				return errCorruptHLL
+			}
+		}
+		c.Reply.Integer(int64(core.CountRegisters(merged)))
+		return nil
+	}
+
+	key := args[1]
+	hll, err := lookupHLLRead(key)
+	if err != nil {
+		return err
+	}
+	if hll == nil {
+		c.Reply.Integer(0)
+		return nil
+	}
+	card, cached := hll.CachedCount()
+	if !cached {
+		var ok bool
+		if card, ok = hll.Count(); !ok {
+			return errCorruptHLL
+		}
+		hll.SetCachedCount(card)
+		setStringKeepTTL(key, string(hll))
+		propagateAs(c, args...)
+	}
+	c.Reply.Integer(int64(card))
+	return nil
+}
@@ -0,0 +1,45 @@
+package commands
+
+import "github.com/codecrafters-io/redis-starter-go/internal/models/core"
+
+// HandlePfmerge merges HyperLogLogs into destkey, which is one of the
+// inputs too: PFMERGE destkey [sourcekey ...]. Each register of the
+// result is the highest of the inputs'. The result is dense if any input
+// is, and sparse for as long as possible otherwise.
+func HandlePfmerge(c *Client, args []string) error {
+	key := args[1]
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	merged := make([]uint8, core.HLLRegisters)
+	dense := false
+	for _, source := range args[1:] {
+		hll, err := lookupHLLRead(source)
+		if err != nil {
+			return err
+		}
+		if hll == nil {
+			continue
+		}
+		dense = dense || hll.IsDense()
+		if !hll.Merge(merged) {
+			return errCorruptHLL
+		}
+	}
+
+	hll, err := lookupHLLWrite(key)
+	if err != nil {
+		return err
+	}
+	if hll == nil {
+		hll = core.NewHyperLogLog()
+	}
+	if !hll.SetRegisters(merged, dense) {
+		return errCorruptHLL
+	}
+	hll.InvalidateCache()
+	setStringKeepTTL(key, string(hll))
+	c.Reply.OK()
+	return nil
+}
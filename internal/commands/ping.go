@@ -1,15 +1,11 @@
 package commands
 
-import (
-	"net"
-)
-
-func HandlePing(conn net.Conn, args []string, isReplica bool) {
-	if isReplica {
-		return
+func HandlePing(c *Client, args []string) error {
+	if len(args) > 1 {
+		c.Reply.Bulk(args[1])
+		return nil
 	}
 
-	if conn != nil {
-		conn.Write([]byte("+PONG\r\n"))
-	}
+	c.Reply.SimpleString("PONG")
+	return nil
 }
@@ -1,74 +1,196 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"strings"
 
 	models "github.com/codecrafters-io/redis-starter-go/internal/models/core"
+	"github.com/codecrafters-io/redis-starter-go/internal/replication"
+	"github.com/codecrafters-io/redis-starter-go/internal/resp"
 )
 
+// Client is the connection a command runs for. Handlers write their reply
+// to c.Reply and never touch the connection directly.
+type Client = models.ClientState
+
+// ProcessCommand runs a command that arrived over a replication link and
+// sends its reply right away. Commands streamed from our master are applied
+// silently; only REPLCONF GETACK is answered.
 func ProcessCommand(conn net.Conn, args []string, isReplica bool) {
+	c := models.LookupClient(conn)
+	c.Master = isReplica
+	Process(c, args, isReplica)
+	c.Reply.Flush()
+}
+
+// Process runs one command for c. The reply is buffered in c.Reply; the
+// caller decides when to flush it.
+func Process(c *Client, args []string, isReplica bool) {
 	command := strings.ToUpper(args[0])
 	fmt.Println("Processing command:", command)
-	// so that it does not consider the DISCARD command in the transaction queueu.
-	if command == "DISCARD" {
-		HandleDiscard(conn)
+
+	// REPLCONF GETACK is the only command the master expects an answer to;
+	// even errors must not be written back on the replication link.
+	if isReplica && command != "REPLCONF" {
+		reply := c.Reply
+		c.Reply = resp.NewWriter(nil)
+		c.Reply.SetProtocol(reply.Protocol())
+		defer func() { c.Reply = reply }()
+	}
+
+	cmd, err := resolveCommand(args)
+	if err != nil {
+		rejectCommand(c, err.Error())
+		return
+	}
+
+	if !isReplica && !cmd.has(flagNoAuth) && authRequired(c) {
+		rejectCommand(c, "NOAUTH Authentication required.")
 		return
 	}
 
 	models.ClientMu.Lock()
-	state, exists := models.ClientStates[conn]
+	inTransaction := c.InTransaction
 	models.ClientMu.Unlock()
 
-	// Queue command if in transaction (excluding MULTI/EXEC)
-	if exists && state.InTransaction && command != "MULTI" && command != "EXEC" {
-		models.ClientMu.Lock()
-		state.CommandQueue = append(state.CommandQueue, args)
-		models.ClientMu.Unlock()
-		conn.Write([]byte("+QUEUED\r\n"))
+	if inTransaction {
+		if cmd.has(flagNoMulti) {
+			rejectCommand(c, "ERR Command not allowed inside a transaction")
+			return
+		}
+		// everything but EXEC and DISCARD is queued
+		if cmd.Name != "exec" && cmd.Name != "discard" {
+			models.ClientMu.Lock()
+			c.CommandQueue = append(c.CommandQueue, args)
+			models.ClientMu.Unlock()
+			c.Reply.SimpleString("QUEUED")
+			return
+		}
+	}
+
+	call(c, cmd, args, isReplica)
+}
+
+// rejectCommand replies with the error of a command that couldn't be run
+// at all. Inside MULTI it also dooms the transaction: its EXEC will fail
+// rather than run only part of what was queued.
+func rejectCommand(c *Client, msg string) {
+	models.ClientMu.Lock()
+	if c.InTransaction {
+		c.DirtyExec = true
+	}
+	models.ClientMu.Unlock()
+	c.Reply.Error(msg)
+}
+
+// resolveCommand finds the command (or subcommand) args invoke and checks
+// its arity.
+func resolveCommand(args []string) (*Command, error) {
+	cmd, ok := lookupCommand(args[0])
+	if !ok {
+		return nil, errors.New(unknownCommandError(args))
+	}
+	if len(cmd.Subcommands) > 0 && (len(args) > 1 || cmd.Handler == nil) {
+		if len(args) == 1 {
+			return nil, fmt.Errorf("ERR wrong number of arguments for '%s' command", cmd.Name)
+		}
+		sub, ok := cmd.subcommand(args[1])
+		if !ok {
+			return nil, fmt.Errorf("ERR unknown subcommand '%s'. Try %s HELP.", args[1], strings.ToUpper(cmd.Name))
+		}
+		cmd = sub
+	}
+	if !cmd.checkArity(len(args)) {
+		return nil, fmt.Errorf("ERR wrong number of arguments for '%s' command", cmd.FullName())
+	}
+	return cmd, nil
+}
+
+// call runs a command that passed all checks: it blocks the client if the
+// command has to wait, propagates writes, and serves clients blocked on
+// keys the command made ready.
+func call(c *Client, cmd *Command, args []string, isReplica bool) {
+	// EXEC takes execMu for writing itself, and the commands it runs come
+	// through here while it holds it. WAIT doesn't touch the keyspace, and
+	// mustn't hold up transactions while it waits for the replicas.
+	locked := !c.InExec && cmd.Name != "exec" && cmd.Name != "wait"
+	if locked {
+		execMu.RLock()
+	}
+	c.Propagate = nil
+	err := cmd.Handler(c, args)
+
+	var block *blockRequest
+	switch {
+	case errors.As(err, &block):
+	case err != nil:
+		c.Reply.Error(err.Error())
+	case (cmd.has(flagWrite) || c.Propagate != nil) && !isReplica:
+		propagate(c, args)
+	}
+	if locked {
+		execMu.RUnlock()
+	}
+
+	if block != nil {
+		if isReplica || c.InExec {
+			block.onTimeout(c)
+			return
+		}
+		blockClient(c, cmd, args, block)
+	}
+	// inside EXEC this waits until the whole transaction is done
+	if !c.InExec {
+		handleClientsBlockedOnKeys()
+	}
+}
+
+// propagate sends a write command to the replicas, or whatever its handler
+// asked to propagate instead with propagateAs or preventPropagation.
+func propagate(c *Client, args []string) {
+	if c.Propagate == nil {
+		propagateCommand(append([]string{strings.ToUpper(args[0])}, args[1:]...))
 		return
 	}
+	for _, argv := range c.Propagate {
+		propagateCommand(argv)
+	}
+	c.Propagate = nil
+}
+
+// propagateCommand sends argv to the replicas, or adds it to the
+// transaction being run by EXEC, which propagates all of it at the end.
+// Callers must hold execMu.
+func propagateCommand(argv []string) {
+	if execPropagate != nil {
+		execPropagate = append(execPropagate, argv)
+		return
+	}
+	replication.PropagateCommand(argv[0], argv[1:])
+}
+
+// propagateAs makes the running command replicate as argv instead of as
+// itself, e.g. EXPIRE as PEXPIREAT with an absolute time so replicas end up
+// with the same deadline. Calling it again adds another command. A
+// read-only command calls it to replicate a side effect, like PFCOUNT
+// caching the cardinality in the value.
+func propagateAs(c *Client, argv ...string) {
+	c.Propagate = append(c.Propagate, argv)
+}
+
+// preventPropagation keeps a write command that turned out not to change
+// anything out of the replication stream.
+func preventPropagation(c *Client) {
+	c.Propagate = [][]string{}
+}
 
-	// otherwise we will execute the commands normally
-	switch command {
-	case "PING":
-		HandlePing(conn, args, isReplica)
-	case "ECHO":
-		HandleEcho(conn, args)
-	case "SET":
-		HandleSet(conn, args, isReplica)
-	case "GET":
-		HandleGet(conn, args)
-	case "CONFIG":
-		HandleConfig(conn, args)
-	case "KEYS":
-		HandleKeys(conn, args)
-	case "INFO":
-		HandleInfo(conn, args)
-	case "REPLCONF":
-		HandleReplConf(conn, args)
-	case "PSYNC":
-		HandlePsync(conn, args)
-	case "WAIT":
-		HandleWait(conn, args, isReplica)
-	case "TYPE":
-		HandleType(conn, args)
-	case "XADD":
-		HandleXadd(conn, args)
-	case "XRANGE":
-		HandleXrange(conn, args)
-	case "XREAD":
-		HandleXread(conn, args)
-	case "INCR":
-		HandleIncr(conn, args)
-	case "MULTI":
-		HandleMulti(conn)
-	case "EXEC":
-		HandleExec(conn)
-	case "DISCARD":
-		HandleDiscard(conn)
-	default:
-		conn.Write([]byte(fmt.Sprintf("-ERR unknown command '%s'\r\n", command)))
+func unknownCommandError(args []string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "ERR unknown command '%s', with args beginning with: ", args[0])
+	for _, arg := range args[1:] {
+		fmt.Fprintf(&sb, "'%s' ", arg)
 	}
+	return sb.String()
 }
@@ -3,8 +3,8 @@ package commands
 import (
 	"bufio"
 	"encoding/hex"
+	"errors"
 	"fmt"
-	"net"
 
 	"github.com/codecrafters-io/redis-starter-go/internal/replication"
 	"github.com/codecrafters-io/redis-starter-go/internal/utils"
@@ -12,50 +12,30 @@ import (
 
 const emptyRDBHex = "524544495330303036ff00006b2471d24e010000" // hardcoded rn, but pick this from the .env file instead
 
-func HandlePsync(conn net.Conn, args []string) {
+func HandlePsync(c *Client, args []string) error {
 	if len(args) != 3 {
-		conn.Write([]byte("-ERR invalid PSYNC command\r\n"))
-		return
-	}
-
-	bw := bufio.NewWriter(conn)
-
-	masterReplID := utils.GetMasterReplID()
-	fullResyncResponse := fmt.Sprintf("+FULLRESYNC %s 0\r\n", masterReplID)
-	if _, err := bw.WriteString(fullResyncResponse); err != nil {
-		conn.Close()
-		return
-	}
-	if err := bw.Flush(); err != nil {
-		conn.Close()
-		return
+		return errors.New("ERR invalid PSYNC command")
 	}
 
 	rdbBytes, err := hex.DecodeString(emptyRDBHex)
 	if err != nil {
-		conn.Write([]byte("-ERR failed to load empty RDB file\r\n"))
-		conn.Close()
-		return
+		return errors.New("ERR failed to load empty RDB file")
 	}
 
-	rdbHeader := fmt.Sprintf("$%d\r\n", len(rdbBytes))
-	if _, err := bw.WriteString(rdbHeader); err != nil {
-		conn.Close()
-		return
-	}
-
-	if _, err := bw.Write(rdbBytes); err != nil {
-		conn.Close()
-		return
-	}
-
-	if err := bw.Flush(); err != nil {
-		conn.Close()
-		return
+	masterReplID := utils.GetMasterReplID()
+	c.Reply.SimpleString(fmt.Sprintf("FULLRESYNC %s 0", masterReplID))
+
+	// the RDB payload is sent like a bulk string, minus the trailing CRLF
+	c.Reply.Raw([]byte(fmt.Sprintf("$%d\r\n", len(rdbBytes))))
+	c.Reply.Raw(rdbBytes)
+	if err := c.Reply.Flush(); err != nil {
+		c.Conn.Close()
+		return nil
 	}
 
-	replication.AddReplica(conn)
-	fmt.Println("[Master] Registered new replica:", conn.RemoteAddr())
+	replication.AddReplica(c.Conn)
+	fmt.Println("[Master] Registered new replica:", c.Conn.RemoteAddr())
 
-	go replication.HandleReplicatedCommands(conn, bufio.NewReader(conn), ProcessCommand)
+	go replication.HandleReplicatedCommands(c.Conn, bufio.NewReader(c.Conn), ProcessCommand)
+	return nil
 }
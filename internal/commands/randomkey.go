@@ -0,0 +1,23 @@
+package commands
+
+func HandleRandomKey(c *Client, args []string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	// expired keys we run into are reclaimed; on a replica they can't be,
+	// so give up after a while if that's all there is
+	for tries := 0; tries < 100; tries++ {
+		key, ok := store.RandomKey()
+		if !ok {
+			break
+		}
+		if expireIfNeeded(key) {
+			continue
+		}
+		c.Reply.Bulk(key)
+		return nil
+	}
+
+	c.Reply.Null()
+	return nil
+}
@@ -0,0 +1,49 @@
+package commands
+
+import "errors"
+
+func HandleRename(c *Client, args []string) error {
+	return renameGeneric(c, args[1], args[2], false)
+}
+
+func HandleRenameNx(c *Client, args []string) error {
+	return renameGeneric(c, args[1], args[2], true)
+}
+
+// renameGeneric moves the value of src, TTL included, to dst, replacing
+// whatever dst held unless nx is set.
+func renameGeneric(c *Client, src, dst string, nx bool) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	entry, exists := lookupKeyWrite(src)
+	if !exists {
+		return errors.New("ERR no such key")
+	}
+
+	if src == dst {
+		preventPropagation(c)
+		if nx {
+			c.Reply.Integer(0)
+		} else {
+			c.Reply.OK()
+		}
+		return nil
+	}
+
+	if _, exists := lookupKeyWrite(dst); exists && nx {
+		preventPropagation(c)
+		c.Reply.Integer(0)
+		return nil
+	}
+
+	deleteKey(src)
+	setKey(dst, entry)
+
+	if nx {
+		c.Reply.Integer(1)
+	} else {
+		c.Reply.OK()
+	}
+	return nil
+}
@@ -1,19 +1,17 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
-	"log"
-	"net"
 	"strconv"
 	"strings"
 
 	"github.com/codecrafters-io/redis-starter-go/internal/replication"
 )
 
-func HandleReplConf(conn net.Conn, args []string) {
+func HandleReplConf(c *Client, args []string) error {
 	if len(args) < 3 {
-		conn.Write([]byte("-ERR invalid REPLCONF command\r\n"))
-		return
+		return errors.New("ERR invalid REPLCONF command")
 	}
 
 	param := strings.ToLower(args[1])
@@ -22,49 +20,35 @@ func HandleReplConf(conn net.Conn, args []string) {
 	case "listening-port":
 
 		if len(args) != 3 {
-			conn.Write([]byte("-ERR missing port argument\r\n"))
-			return
+			return errors.New("ERR missing port argument")
 		}
 		fmt.Println("Replica reported listening port:", args[2])
 
-		replication.AddReplica(conn)
-		conn.Write([]byte("+OK\r\n"))
-		return
+		replication.AddReplica(c.Conn)
+		c.Reply.OK()
+		return nil
 
 	case "capa":
 		if len(args) != 3 {
-			conn.Write([]byte("-ERR missing capa argument\r\n"))
-			return
+			return errors.New("ERR missing capa argument")
 		}
 		fmt.Println("Replica supports capability:", args[2])
 
 	case "getack":
 		offsetValue := replication.GetOffset()
 		offsetStr := strconv.FormatInt(offsetValue, 10)
-		ackResponse := fmt.Sprintf(
-			"*3\r\n$8\r\nREPLCONF\r\n$3\r\nACK\r\n$%d\r\n%s\r\n",
-			len(offsetStr),
-			offsetStr,
-		)
-
-		if _, err := conn.Write([]byte(ackResponse)); err != nil {
-			log.Println("Failed to send ACK:", err)
-		}
-		return
+		c.Reply.BulkArray([]string{"REPLCONF", "ACK", offsetStr})
+		return nil
 
 	case "ack":
-		if len(args) < 3 {
-			conn.Write([]byte("-ERR missing offset\r\n"))
-			return
-		}
 		ackOffset, _ := strconv.ParseInt(args[2], 10, 64)
-		replication.SetReplicaOffset(conn, ackOffset)
-		return
+		replication.SetReplicaOffset(c.Conn, ackOffset)
+		return nil
 
 	default:
-		conn.Write([]byte("-ERR unknown REPLCONF parameter\r\n"))
-		return
+		return errors.New("ERR unknown REPLCONF parameter")
 	}
 
-	conn.Write([]byte("+OK\r\n"))
+	c.Reply.OK()
+	return nil
 }
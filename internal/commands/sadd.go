@@ -0,0 +1,27 @@
+package commands
+
+// HandleSadd adds members to a set, replying with how many weren't there
+// yet: SADD key member [member ...].
+func HandleSadd(c *Client, args []string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	set, err := lookupOrCreateSet(args[1])
+	if err != nil {
+		return err
+	}
+	added := 0
+	for _, member := range args[2:] {
+		if set.Add(member) {
+			added++
+		}
+	}
+
+	if added == 0 {
+		preventPropagation(c)
+	} else {
+		signalModifiedKey(args[1])
+	}
+	c.Reply.Integer(int64(added))
+	return nil
+}
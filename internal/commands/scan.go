@@ -0,0 +1,138 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+	"github.com/codecrafters-io/redis-starter-go/internal/utils"
+)
+
+// scanOptions are the [MATCH pattern] [COUNT count] [TYPE type] options of
+// SCAN and its per-type variants, and HSCAN's [NOVALUES].
+type scanOptions struct {
+	pattern  string
+	count    int
+	typ      string
+	noValues bool
+}
+
+// HandleScan walks the keyspace incrementally: SCAN cursor [MATCH pattern]
+// [COUNT count] [TYPE type]. Each call holds the lock only for about COUNT
+// buckets, and the cursor guarantees that a key present for the whole scan
+// is returned, see core.Dict.Scan.
+func HandleScan(c *Client, args []string) error {
+	cursor, err := parseScanCursor(args[1])
+	if err != nil {
+		return err
+	}
+	opts, err := parseScanOptions(args[2:], "")
+	if err != nil {
+		return err
+	}
+
+	keys := []string{}
+	now := time.Now().UnixMilli()
+
+	mu.RLock()
+	for maxIterations := opts.maxIterations(); ; maxIterations-- {
+		cursor = store.Scan(cursor, func(key string, entry core.StoreEntry) {
+			if isExpired(entry, now) || (opts.typ != "" && entry.Type != opts.typ) {
+				return
+			}
+			if opts.matches(key) {
+				keys = append(keys, key)
+			}
+		})
+		if cursor == 0 || maxIterations <= 1 || len(keys) >= opts.count {
+			break
+		}
+	}
+	mu.RUnlock()
+
+	replyScan(c, cursor, keys)
+	return nil
+}
+
+func parseScanCursor(arg string) (uint64, error) {
+	cursor, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil {
+		return 0, errors.New("ERR invalid cursor")
+	}
+	return cursor, nil
+}
+
+// parseScanOptions parses the options after the cursor of the command
+// scanning a value of type typ, "" for SCAN itself: TYPE is only accepted
+// by SCAN, NOVALUES only by HSCAN.
+func parseScanOptions(args []string, typ string) (scanOptions, error) {
+	opts := scanOptions{count: 10}
+	for i := 0; i < len(args); i++ {
+		if typ == "hash" && strings.ToUpper(args[i]) == "NOVALUES" {
+			opts.noValues = true
+			continue
+		}
+		if i+1 == len(args) {
+			return scanOptions{}, errSyntax
+		}
+		switch strings.ToUpper(args[i]) {
+		case "MATCH":
+			i++
+			opts.pattern = args[i]
+		case "COUNT":
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil {
+				return scanOptions{}, errNotInteger
+			}
+			if n < 1 {
+				return scanOptions{}, errSyntax
+			}
+			opts.count = n
+		case "TYPE":
+			if typ != "" {
+				return scanOptions{}, errSyntax
+			}
+			i++
+			opts.typ = strings.ToLower(args[i])
+			if !knownType(opts.typ) {
+				return scanOptions{}, fmt.Errorf("ERR unknown type name '%s'", args[i])
+			}
+		default:
+			return scanOptions{}, errSyntax
+		}
+	}
+	return opts, nil
+}
+
+// maxIterations bounds how many buckets one call may visit looking for
+// COUNT matches: ten times COUNT, saturating instead of overflowing.
+func (o scanOptions) maxIterations() int {
+	if o.count > math.MaxInt/10 {
+		return math.MaxInt
+	}
+	return o.count * 10
+}
+
+func (o scanOptions) matches(s string) bool {
+	return o.pattern == "" || o.pattern == "*" || utils.GlobMatch(o.pattern, s, false)
+}
+
+func knownType(name string) bool {
+	switch name {
+	case "string", "list", "set", "zset", "hash", "stream":
+		return true
+	}
+	return false
+}
+
+// replyScan writes the [next cursor, items] reply of the SCAN family.
+func replyScan(c *Client, cursor uint64, items []string) {
+	c.Reply.ArrayLen(2)
+	c.Reply.Bulk(strconv.FormatUint(cursor, 10))
+	c.Reply.BulkArray(items)
+}
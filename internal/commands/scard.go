@@ -0,0 +1,17 @@
+package commands
+
+func HandleScard(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	set, err := lookupSetRead(args[1])
+	if err != nil {
+		return err
+	}
+	if set == nil {
+		c.Reply.Integer(0)
+		return nil
+	}
+	c.Reply.Integer(int64(set.Len()))
+	return nil
+}
@@ -2,70 +2,132 @@ package commands
 
 import (
 	"fmt"
-	"net"
+	"math"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
-	models "github.com/codecrafters-io/redis-starter-go/internal/models/core"
-	"github.com/codecrafters-io/redis-starter-go/internal/replication"
 )
 
-var (
-	db = make(map[string]string)
-)
+// setOptions is the parsed form of SET's
+// [NX | XX] [GET] [EX s | PX ms | EXAT ts | PXAT ms-ts | KEEPTTL] options.
+type setOptions struct {
+	nx, xx   bool
+	get      bool
+	keepTTL  bool
+	expireAt int64 // unix ms, 0 for no expiry
+}
 
-func HandleSet(conn net.Conn, args []string, isReplica bool) {
-	models.ClientMu.Lock()
-	state, exists := models.ClientStates[conn]
-	models.ClientMu.Unlock()
+func HandleSet(c *Client, args []string) error {
+	key := args[1]
+	value := args[2]
 
-	if exists && state.InTransaction {
-		// here we are just returning because it is already in transaction
-		return
+	opts, err := parseSetOptions(args)
+	if err != nil {
+		return err
 	}
 
-	if len(args) < 3 {
-		if conn != nil {
-			conn.Write([]byte("-ERR wrong number of arguments for 'SET' command\r\n"))
-		}
-		return
+	mu.Lock()
+	defer mu.Unlock()
+
+	entry, exists := lookupKeyWrite(key)
+	if opts.get && exists && entry.Type != "string" {
+		return errWrongType
 	}
 
-	key := args[1]
-	value := args[2]
-	var ttl int64 = 0
+	if (opts.nx && exists) || (opts.xx && !exists) {
+		preventPropagation(c)
+		replySetOld(c, opts, entry, exists)
+		return nil
+	}
 
-	if len(args) >= 5 && strings.ToUpper(args[3]) == "PX" {
-		parsedTTL, err := strconv.ParseInt(args[4], 10, 64)
-		if err != nil || parsedTTL <= 0 {
-			if conn != nil {
-				conn.Write([]byte("-ERR PX must be a positive integer\r\n"))
-			}
-			return
-		}
-		ttl = parsedTTL
+	newEntry := core.StoreEntry{Data: value, Type: "string", ExpiresAt: opts.expireAt}
+	if opts.keepTTL {
+		newEntry.ExpiresAt = entry.ExpiresAt
 	}
+	setKey(key, newEntry)
 
-	entry := core.StoreEntry{Data: value, Type: "string"}
-	if ttl > 0 {
-		entry.ExpiresAt = time.Now().UnixMilli() + ttl
+	// relative times are replicated as an absolute one
+	if opts.expireAt > 0 {
+		propagateAs(c, "SET", key, value, "PXAT", strconv.FormatInt(opts.expireAt, 10))
 	}
 
-	if isReplica {
-		SetKeyEntry(key, entry)
-		fmt.Println("[Replica] Stored key:", key, "Value:", value, "TTL:", ttl)
+	if opts.get {
+		replySetOld(c, opts, entry, exists)
 	} else {
-		SetKey(key, value, ttl)
+		c.Reply.OK()
+	}
 
-		if conn != nil {
-			conn.Write([]byte("+OK\r\n"))
+	fmt.Println("Processed SET:", key, "->", value, "ExpiresAt:", newEntry.ExpiresAt)
+	return nil
+}
+
+// replySetOld replies with the previous value for SET ... GET, or with nil
+// when SET didn't happen.
+func replySetOld(c *Client, opts setOptions, entry core.StoreEntry, exists bool) {
+	if opts.get && exists {
+		c.Reply.Bulk(entry.Data.(string))
+		return
+	}
+	c.Reply.Null()
+}
+
+func parseSetOptions(args []string) (setOptions, error) {
+	var opts setOptions
+	expireSet := false
+
+	for i := 3; i < len(args); i++ {
+		opt := strings.ToUpper(args[i])
+		switch {
+		case opt == "NX" && !opts.xx:
+			opts.nx = true
+		case opt == "XX" && !opts.nx:
+			opts.xx = true
+		case opt == "GET":
+			opts.get = true
+		case opt == "KEEPTTL" && !expireSet:
+			opts.keepTTL = true
+		case (opt == "EX" || opt == "PX" || opt == "EXAT" || opt == "PXAT") &&
+			!expireSet && !opts.keepTTL && i+1 < len(args):
+			i++
+			at, err := parseSetExpire("set", opt, args[i])
+			if err != nil {
+				return setOptions{}, err
+			}
+			opts.expireAt = at
+			expireSet = true
+		default:
+			return setOptions{}, errSyntax
 		}
+	}
+	return opts, nil
+}
 
-		fmt.Println("[Master] Processed SET:", key, "->", value, "TTL:", ttl)
+// parseSetExpire turns the argument of EX/PX/EXAT/PXAT into a unix time in
+// ms. name is the command, for the error.
+func parseSetExpire(name, opt, arg string) (int64, error) {
+	n, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return 0, errNotInteger
+	}
+	errExpire := fmt.Errorf("ERR invalid expire time in '%s' command", name)
+	if n <= 0 {
+		return 0, errExpire
+	}
 
-		replication.PropagateCommand("SET", args[1:])
-		// time.Sleep(100 * time.Millisecond)
+	if opt == "EX" || opt == "EXAT" {
+		if n > math.MaxInt64/1000 {
+			return 0, errExpire
+		}
+		n *= 1000
+	}
+	if opt == "EX" || opt == "PX" {
+		now := time.Now().UnixMilli()
+		if n > math.MaxInt64-now {
+			return 0, errExpire
+		}
+		n += now
 	}
+	return n, nil
 }
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"errors"
+	"strconv"
+)
+
+// HandleSetbit sets or clears the bit at offset, replying with its old
+// value: SETBIT key offset value.
+func HandleSetbit(c *Client, args []string) error {
+	key := args[1]
+	offset, err := parseBitOffset(args[2], false, 0)
+	if err != nil {
+		return err
+	}
+	on, err := strconv.Atoi(args[3])
+	if err != nil || on&^1 != 0 {
+		return errors.New("ERR bit is not an integer or out of range")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	value, _, err := lookupStringWrite(key)
+	if err != nil {
+		return err
+	}
+	old := getBit(value, offset)
+	b := growForBit(value, offset)
+	setBit(b, offset, on)
+	setStringKeepTTL(key, string(b))
+
+	c.Reply.Integer(int64(old))
+	return nil
+}
+
+// HandleGetbit replies with the bit at offset: GETBIT key offset.
+func HandleGetbit(c *Client, args []string) error {
+	offset, err := parseBitOffset(args[2], false, 0)
+	if err != nil {
+		return err
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	value, _, err := lookupStringRead(args[1])
+	if err != nil {
+		return err
+	}
+	c.Reply.Integer(int64(getBit(value, offset)))
+	return nil
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+)
+
+// HandleSetnx sets key only if it doesn't exist, replying with 1 if it
+// did: SETNX key value.
+func HandleSetnx(c *Client, args []string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, exists := lookupKeyWrite(args[1]); exists {
+		preventPropagation(c)
+		c.Reply.Integer(0)
+		return nil
+	}
+	setKey(args[1], core.StoreEntry{Type: "string", Data: args[2]})
+	c.Reply.Integer(1)
+	return nil
+}
+
+func HandleSetex(c *Client, args []string) error {
+	return setexGeneric(c, args, "EX")
+}
+
+func HandlePsetex(c *Client, args []string) error {
+	return setexGeneric(c, args, "PX")
+}
+
+// setexGeneric implements SETEX key seconds value and PSETEX key ms value,
+// which are SET key value EX|PX time, and replicated like it.
+func setexGeneric(c *Client, args []string, unit string) error {
+	key, value := args[1], args[3]
+	expireAt, err := parseSetExpire(strings.ToLower(args[0]), unit, args[2])
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	setKey(key, core.StoreEntry{Type: "string", Data: value, ExpiresAt: expireAt})
+	propagateAs(c, "SET", key, value, "PXAT", strconv.FormatInt(expireAt, 10))
+	c.Reply.OK()
+	return nil
+}
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser"
+)
+
+// HandleSetrange overwrites the string at key from offset on with value,
+// padding it with zero bytes if it's shorter than offset, and replies with
+// the new length: SETRANGE key offset value.
+func HandleSetrange(c *Client, args []string) error {
+	key, value := args[1], args[3]
+	offset, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return errNotInteger
+	}
+	if offset < 0 {
+		return errors.New("ERR offset is out of range")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	current, _, err := lookupStringWrite(key)
+	if err != nil {
+		return err
+	}
+	// an empty value changes nothing, and doesn't create the key
+	if value == "" {
+		preventPropagation(c)
+		c.Reply.Integer(int64(len(current)))
+		return nil
+	}
+	// offset+len(value) could overflow
+	if offset > parser.MaxBulkLen()-int64(len(value)) {
+		return errStringTooLong
+	}
+
+	var b strings.Builder
+	size := max(int(offset)+len(value), len(current))
+	b.Grow(size)
+	if int(offset) <= len(current) {
+		b.WriteString(current[:offset])
+	} else {
+		b.WriteString(current)
+		b.Write(make([]byte, int(offset)-len(current)))
+	}
+	b.WriteString(value)
+	if size > b.Len() {
+		b.WriteString(current[b.Len():])
+	}
+
+	setStringKeepTTL(key, b.String())
+	c.Reply.Integer(int64(size))
+	return nil
+}
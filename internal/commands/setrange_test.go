@@ -0,0 +1,16 @@
+package commands
+
+import "testing"
+
+func TestSetrangeHugeOffset(t *testing.T) {
+	c := newTestClient()
+	for _, offset := range []string{"9223372036854775807", "9223372036854775806", "536870912"} {
+		got := c.do("SETRANGE", "setrange:huge", offset, "x")
+		if want := "-ERR string exceeds maximum allowed size (proto-max-bulk-len)\r\n"; got != want {
+			t.Errorf("SETRANGE at %s = %q, want %q", offset, got, want)
+		}
+	}
+	if got := c.do("SETRANGE", "setrange:huge", "3", "x"); got != ":4\r\n" {
+		t.Errorf("SETRANGE = %q, want :4", got)
+	}
+}
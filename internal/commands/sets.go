@@ -0,0 +1,80 @@
+package commands
+
+import "github.com/codecrafters-io/redis-starter-go/internal/models/core"
+
+// Helpers shared by the set commands. Like the other keyspace helpers
+// they expect the caller to hold mu.
+
+// lookupSetRead returns the set at key, nil if there is none.
+func lookupSetRead(key string) (*core.Set, error) {
+	entry, exists := lookupKeyRead(key)
+	return setFromEntry(entry, exists)
+}
+
+// lookupSetWrite is lookupSetRead for commands that modify the set.
+func lookupSetWrite(key string) (*core.Set, error) {
+	entry, exists := lookupKeyWrite(key)
+	return setFromEntry(entry, exists)
+}
+
+func setFromEntry(entry core.StoreEntry, exists bool) (*core.Set, error) {
+	if !exists {
+		return nil, nil
+	}
+	if entry.Type != "set" {
+		return nil, errWrongType
+	}
+	return entry.Data.(*core.Set), nil
+}
+
+// lookupOrCreateSet returns the set at key, storing a new empty one if
+// there is none.
+func lookupOrCreateSet(key string) (*core.Set, error) {
+	set, err := lookupSetWrite(key)
+	if err != nil || set != nil {
+		return set, err
+	}
+	set = core.NewSet()
+	setKey(key, core.StoreEntry{Type: "set", Data: set})
+	return set, nil
+}
+
+// deleteSetIfEmpty removes key once its set has no members left, as an
+// empty set is never stored.
+func deleteSetIfEmpty(key string, set *core.Set) {
+	if set.Len() == 0 {
+		deleteKey(key)
+	}
+}
+
+// storeSet replaces whatever is at key with set, the result of one of the
+// *STORE commands; an empty result deletes key.
+func storeSet(key string, set *core.Set) {
+	if set.Len() == 0 {
+		deleteKey(key)
+		return
+	}
+	setKey(key, core.StoreEntry{Type: "set", Data: set})
+}
+
+// lookupSets returns the sets at keys, nil for the missing ones. It fails
+// if any of the keys holds something else.
+func lookupSets(keys []string) ([]*core.Set, error) {
+	sets := make([]*core.Set, len(keys))
+	for i, key := range keys {
+		set, err := lookupSetRead(key)
+		if err != nil {
+			return nil, err
+		}
+		sets[i] = set
+	}
+	return sets, nil
+}
+
+// replySet writes members as a set (an array in RESP2).
+func replySet(c *Client, members []string) {
+	c.Reply.SetLen(len(members))
+	for _, member := range members {
+		c.Reply.Bulk(member)
+	}
+}
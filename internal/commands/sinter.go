@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+)
+
+func HandleSinter(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	sets, err := lookupSets(args[1:])
+	if err != nil {
+		return err
+	}
+	replySet(c, setInter(sets, 0).Members())
+	return nil
+}
+
+// HandleSinterstore stores the intersection of sets at destination:
+// SINTERSTORE destination key [key ...].
+func HandleSinterstore(c *Client, args []string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	sets, err := lookupSets(args[2:])
+	if err != nil {
+		return err
+	}
+	result := setInter(sets, 0)
+	storeSet(args[1], result)
+	c.Reply.Integer(int64(result.Len()))
+	return nil
+}
+
+// HandleSintercard replies with the size of the intersection of sets,
+// counting up to limit at most: SINTERCARD numkeys key [key ...]
+// [LIMIT limit].
+func HandleSintercard(c *Client, args []string) error {
+	numkeys, err := strconv.Atoi(args[1])
+	if err != nil || numkeys <= 0 {
+		return errors.New("ERR numkeys should be greater than 0")
+	}
+	if numkeys > len(args)-2 {
+		return errors.New("ERR Number of keys can't be greater than number of args")
+	}
+
+	limit := 0
+	for i := 2 + numkeys; i < len(args); i++ {
+		if !strings.EqualFold(args[i], "LIMIT") || i+1 == len(args) {
+			return errSyntax
+		}
+		i++
+		limit, err = strconv.Atoi(args[i])
+		if err != nil || limit < 0 {
+			return errors.New("ERR LIMIT can't be negative")
+		}
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	sets, err := lookupSets(args[2 : 2+numkeys])
+	if err != nil {
+		return err
+	}
+	c.Reply.Integer(int64(setInter(sets, limit).Len()))
+	return nil
+}
+
+// sintercardKeys finds the keys of SINTERCARD for COMMAND GETKEYS.
+func sintercardKeys(args []string) []int {
+	return numkeysKeys(args, 1)
+}
+
+// setInter intersects sets, nil standing for an empty one, stopping once
+// the result has limit members if limit isn't 0. It walks the smallest set
+// and checks its members against the others, smallest first.
+func setInter(sets []*core.Set, limit int) *core.Set {
+	result := core.NewSet()
+	if slices.Contains(sets, nil) {
+		return result
+	}
+
+	sets = slices.Clone(sets)
+	slices.SortFunc(sets, func(a, b *core.Set) int {
+		return a.Len() - b.Len()
+	})
+	sets[0].Range(func(member string) bool {
+		for _, other := range sets[1:] {
+			if !other.Contains(member) {
+				return true
+			}
+		}
+		result.Add(member)
+		return limit == 0 || result.Len() < limit
+	})
+	return result
+}
@@ -0,0 +1,40 @@
+package commands
+
+func HandleSismember(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	set, err := lookupSetRead(args[1])
+	if err != nil {
+		return err
+	}
+	if set != nil && set.Contains(args[2]) {
+		c.Reply.Integer(1)
+	} else {
+		c.Reply.Integer(0)
+	}
+	return nil
+}
+
+// HandleSmismember is SISMEMBER for several members at once:
+// SMISMEMBER key member [member ...].
+func HandleSmismember(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	set, err := lookupSetRead(args[1])
+	if err != nil {
+		return err
+	}
+
+	members := args[2:]
+	c.Reply.ArrayLen(len(members))
+	for _, member := range members {
+		if set != nil && set.Contains(member) {
+			c.Reply.Integer(1)
+		} else {
+			c.Reply.Integer(0)
+		}
+	}
+	return nil
+}
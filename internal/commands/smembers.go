@@ -0,0 +1,17 @@
+package commands
+
+func HandleSmembers(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	set, err := lookupSetRead(args[1])
+	if err != nil {
+		return err
+	}
+	if set == nil {
+		replySet(c, nil)
+		return nil
+	}
+	replySet(c, set.Members())
+	return nil
+}
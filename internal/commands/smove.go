@@ -0,0 +1,42 @@
+package commands
+
+// HandleSmove moves a member from one set to another:
+// SMOVE source destination member.
+func HandleSmove(c *Client, args []string) error {
+	src, dst, member := args[1], args[2], args[3]
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	srcSet, err := lookupSetWrite(src)
+	if err != nil {
+		return err
+	}
+	dstSet, err := lookupSetWrite(dst)
+	if err != nil {
+		return err
+	}
+
+	if srcSet == nil || !srcSet.Contains(member) {
+		preventPropagation(c)
+		c.Reply.Integer(0)
+		return nil
+	}
+	// moving to the same set changes nothing, but still counts as moved
+	if src == dst {
+		preventPropagation(c)
+		c.Reply.Integer(1)
+		return nil
+	}
+
+	srcSet.Remove(member)
+	deleteSetIfEmpty(src, srcSet)
+	if dstSet == nil {
+		dstSet, _ = lookupOrCreateSet(dst)
+	}
+	dstSet.Add(member)
+	signalModifiedKey(src)
+	signalModifiedKey(dst)
+	c.Reply.Integer(1)
+	return nil
+}
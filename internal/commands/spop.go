@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"errors"
+	"strconv"
+)
+
+// HandleSpop removes and returns random members of a set:
+// SPOP key [count]. It's replicated as the SREM (or DEL) of what it
+// picked, so that replicas don't make picks of their own.
+func HandleSpop(c *Client, args []string) error {
+	key := args[1]
+	if len(args) > 3 {
+		return errSyntax
+	}
+
+	count, withCount := 1, len(args) == 3
+	if withCount {
+		n, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			return errNotInteger
+		}
+		if n < 0 {
+			return errors.New("ERR value is out of range, must be positive")
+		}
+		count = int(n)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	set, err := lookupSetWrite(key)
+	if err != nil {
+		return err
+	}
+	if set == nil || count == 0 {
+		preventPropagation(c)
+		if withCount {
+			replySet(c, nil)
+		} else {
+			c.Reply.Null()
+		}
+		return nil
+	}
+
+	var popped []string
+	if count >= set.Len() {
+		popped = set.Members()
+		deleteKey(key)
+		propagateAs(c, "DEL", key)
+	} else {
+		for len(popped) < count {
+			member, _ := set.Random()
+			set.Remove(member)
+			popped = append(popped, member)
+		}
+		signalModifiedKey(key)
+		propagateAs(c, append([]string{"SREM", key}, popped...)...)
+	}
+
+	if withCount {
+		replySet(c, popped)
+	} else {
+		c.Reply.Bulk(popped[0])
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"math/rand/v2"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+)
+
+// HandleSrandmember replies with random members of a set:
+// SRANDMEMBER key [count]. A positive count returns distinct members, at
+// most all of them; a negative one returns exactly -count members that
+// may repeat.
+func HandleSrandmember(c *Client, args []string) error {
+	if len(args) > 3 {
+		return errSyntax
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	set, err := lookupSetRead(args[1])
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 2 {
+		if set == nil {
+			c.Reply.Null()
+			return nil
+		}
+		member, _ := set.Random()
+		c.Reply.Bulk(member)
+		return nil
+	}
+
+	count, err := parseInt(args[2])
+	if err != nil {
+		return err
+	}
+	if set == nil || count == 0 {
+		c.Reply.ArrayLen(0)
+		return nil
+	}
+
+	var members []string
+	switch {
+	case count < 0:
+		for i := 0; i < -count; i++ {
+			member, _ := set.Random()
+			members = append(members, member)
+		}
+	case count >= set.Len():
+		members = set.Members()
+	default:
+		members = srandmemberDistinct(set, count)
+	}
+	c.Reply.BulkArray(members)
+	return nil
+}
+
+// srandmemberDistinct picks count distinct members, count < set.Len(). When
+// that's most of the set, it's cheaper to drop random members from a copy
+// than to keep drawing until enough different ones came up.
+func srandmemberDistinct(set *core.Set, count int) []string {
+	if count*3 > set.Len() {
+		members := set.Members()
+		for len(members) > count {
+			i := rand.IntN(len(members))
+			members[i] = members[len(members)-1]
+			members = members[:len(members)-1]
+		}
+		return members
+	}
+
+	members := make([]string, 0, count)
+	picked := make(map[string]bool, count)
+	for len(members) < count {
+		member, _ := set.Random()
+		if picked[member] {
+			continue
+		}
+		picked[member] = true
+		members = append(members, member)
+	}
+	return members
+}
@@ -0,0 +1,33 @@
+package commands
+
+// HandleSrem removes members from a set, and the key with its last member:
+// SREM key member [member ...].
+func HandleSrem(c *Client, args []string) error {
+	key := args[1]
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	set, err := lookupSetWrite(key)
+	if err != nil {
+		return err
+	}
+
+	removed := 0
+	if set != nil {
+		for _, member := range args[2:] {
+			if set.Remove(member) {
+				removed++
+			}
+		}
+		deleteSetIfEmpty(key, set)
+	}
+
+	if removed == 0 {
+		preventPropagation(c)
+	} else {
+		signalModifiedKey(key)
+	}
+	c.Reply.Integer(int64(removed))
+	return nil
+}
@@ -0,0 +1,42 @@
+package commands
+
+// HandleSscan iterates over the members of a set: SSCAN key cursor
+// [MATCH pattern] [COUNT count]. A set small enough to be an intset is
+// returned whole, with cursor 0.
+func HandleSscan(c *Client, args []string) error {
+	cursor, err := parseScanCursor(args[2])
+	if err != nil {
+		return err
+	}
+	opts, err := parseScanOptions(args[3:], "set")
+	if err != nil {
+		return err
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	set, err := lookupSetRead(args[1])
+	if err != nil {
+		return err
+	}
+	members := []string{}
+	if set == nil {
+		replyScan(c, 0, members)
+		return nil
+	}
+
+	for maxIterations := opts.maxIterations(); ; maxIterations-- {
+		cursor = set.Scan(cursor, func(member string) {
+			if opts.matches(member) {
+				members = append(members, member)
+			}
+		})
+		if cursor == 0 || maxIterations <= 1 || len(members) >= opts.count {
+			break
+		}
+	}
+
+	replyScan(c, cursor, members)
+	return nil
+}
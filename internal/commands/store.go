@@ -9,7 +9,8 @@ import (
 )
 
 var (
-	store   = make(map[string]core.StoreEntry)
+	store   = core.NewDict[core.StoreEntry]()
+	expires = make(map[string]int64) // key -> unix ms, for keys with a TTL
 	mu      sync.RWMutex
 	configs = map[string]string{
 		"dir":        "/tmp", // setting this to tmp for now.
@@ -17,20 +18,10 @@ var (
 	}
 	replicas []net.Conn
 
-	waitingClientsMu sync.Mutex
-	waitingClients   = make(map[string][]*waitingClient)
+	// key -> unix ms when its first field expires, for hashes with field TTLs
+	hashFieldExpires = make(map[string]int64)
 )
 
-type waitingClient struct {
-	streams    map[string]string
-	responseCh chan xreadResponse
-	deadline   time.Time
-}
-
-type xreadResponse struct {
-	entries map[string][]core.StreamEntry
-}
-
 func SetKey(key, value string, ttl int64) {
 	mu.Lock()
 	defer mu.Unlock()
@@ -39,41 +30,11 @@ func SetKey(key, value string, ttl int64) {
 	if ttl > 0 {
 		entry.ExpiresAt = time.Now().UnixMilli() + ttl // Expiry in milliseconds, should i make it in seconds?
 	}
-	store[key] = entry
+	setKey(key, entry)
 }
 
 func GetKey(key string) (core.StoreEntry, bool) {
-	mu.RLock()
-	entry, exists := store[key]
-	mu.RUnlock()
-
-	if !exists {
-		return core.StoreEntry{}, false
-	}
-
-	if entry.ExpiresAt > 0 && time.Now().UnixMilli() > entry.ExpiresAt {
-		mu.Lock()
-		delete(store, key)
-		mu.Unlock()
-		return core.StoreEntry{}, false
-
-		// TODO: Handle the expiration part in a better way.
-		/*
-			the current impl will delete the keys from the store when the user try to access it
-			however if the user does not try to access the key, the key will not be deleted from the store
-			for this imo what we can do in the future is to have a cron job that will delete the exp. keys form the store.
-
-			ISSUES IN CRON IMPL:
-			1. somehow we have to make sure that the cron job is not breaking the main thread
-			2. we make to make the crons thead safe
-			3. we have to make sure that the cron job is not using too much CPU
-			4. and also make sure that in case of too much load on the server the cron job is not started,
-			   dynamically adjust the cron for the memory usage
-			5. make sure to delete the keys in batch and not 1 by 1 to avoide the overhead
-		*/
-	}
-
-	return entry, true
+	return GetEntry(key)
 }
 
 func SetConfig(key, value string) {
@@ -92,30 +53,97 @@ func GetConfig(key string) (string, bool) {
 func SetKeyEntry(key string, entry core.StoreEntry) {
 	mu.Lock()
 	defer mu.Unlock()
-	store[key] = entry
+	setKey(key, entry)
 }
 
 func ClearStore() {
 	mu.Lock()
 	defer mu.Unlock()
-	store = make(map[string]core.StoreEntry)
+	flushStore()
+}
+
+// flushStore removes every key. Callers must hold the write lock on mu.
+func flushStore() {
+	signalFlushedKeys()
+	store = core.NewDict[core.StoreEntry]()
+	expires = make(map[string]int64)
+	hashFieldExpires = make(map[string]int64)
 }
 
 func GetEntry(key string) (core.StoreEntry, bool) {
 	mu.RLock()
-	entry, exists := store[key]
+	entry, exists := store.Get(key)
 	mu.RUnlock()
 
 	if !exists {
 		return core.StoreEntry{}, false
 	}
 
-	if entry.ExpiresAt > 0 && time.Now().UnixMilli() > entry.ExpiresAt {
+	if isExpired(entry, time.Now().UnixMilli()) {
 		mu.Lock()
-		delete(store, key)
+		expireIfNeeded(key)
 		mu.Unlock()
 		return core.StoreEntry{}, false
 	}
 
 	return entry, true
 }
+
+// The helpers below work on the keyspace directly; callers must hold mu
+// (the write lock for anything that can modify it).
+
+// lookupKeyRead returns the entry for key, treating an expired key as
+// missing without removing it.
+func lookupKeyRead(key string) (core.StoreEntry, bool) {
+	entry, exists := store.Get(key)
+	if !exists || isExpired(entry, time.Now().UnixMilli()) {
+		return core.StoreEntry{}, false
+	}
+	return entry, true
+}
+
+// lookupKeyWrite returns the entry for key, deleting it first if it has
+// expired.
+func lookupKeyWrite(key string) (core.StoreEntry, bool) {
+	if expireIfNeeded(key) {
+		return core.StoreEntry{}, false
+	}
+	entry, exists := store.Get(key)
+	return entry, exists
+}
+
+// setKey stores entry under key, keeping the expires indexes in sync with
+// its TTL and those of its fields, and wakes up clients blocked on key.
+func setKey(key string, entry core.StoreEntry) {
+	store.Set(key, entry)
+	signalModifiedKey(key)
+	signalKeyAsReady(key)
+	if entry.ExpiresAt > 0 {
+		expires[key] = entry.ExpiresAt
+	} else {
+		delete(expires, key)
+	}
+	if hash, ok := entry.Data.(*core.Hash); ok {
+		updateHashFieldExpires(key, hash)
+	} else {
+		delete(hashFieldExpires, key)
+	}
+}
+
+// setExpire sets the TTL of an existing key to the unix time when (in
+// ms); 0 removes it.
+func setExpire(key string, when int64) {
+	entry, _ := store.Get(key)
+	entry.ExpiresAt = when
+	setKey(key, entry)
+}
+
+func deleteKey(key string) bool {
+	delete(expires, key)
+	delete(hashFieldExpires, key)
+	if !store.Delete(key) {
+		return false
+	}
+	signalModifiedKey(key)
+	return true
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser"
+)
+
+// Helpers shared by the string commands. Like the other keyspace helpers
+// they expect the caller to hold mu.
+
+var errStringTooLong = errors.New("ERR string exceeds maximum allowed size (proto-max-bulk-len)")
+
+// lookupStringRead returns the string at key, false if there is none.
+func lookupStringRead(key string) (string, bool, error) {
+	entry, exists := lookupKeyRead(key)
+	return stringFromEntry(entry, exists)
+}
+
+// lookupStringWrite is lookupStringRead for commands that modify the
+// string.
+func lookupStringWrite(key string) (string, bool, error) {
+	entry, exists := lookupKeyWrite(key)
+	return stringFromEntry(entry, exists)
+}
+
+func stringFromEntry(entry core.StoreEntry, exists bool) (string, bool, error) {
+	if !exists {
+		return "", false, nil
+	}
+	if entry.Type != "string" {
+		return "", false, errWrongType
+	}
+	return entry.Data.(string), true, nil
+}
+
+// setStringKeepTTL stores value at key, keeping the TTL the key has, if
+// any: modifying a string in place doesn't reset it.
+func setStringKeepTTL(key, value string) {
+	entry, _ := store.Get(key)
+	setKey(key, core.StoreEntry{Type: "string", Data: value, ExpiresAt: entry.ExpiresAt})
+}
+
+// checkStringLength fails if a string would grow past proto-max-bulk-len,
+// as it could then never be read back.
+func checkStringLength(size int64) error {
+	if size > parser.MaxBulkLen() {
+		return errStringTooLong
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package commands
+
+func HandleStrlen(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	value, _, err := lookupStringRead(args[1])
+	if err != nil {
+		return err
+	}
+	c.Reply.Integer(int64(len(value)))
+	return nil
+}
@@ -0,0 +1,80 @@
+package commands
+
+import "github.com/codecrafters-io/redis-starter-go/internal/models/core"
+
+func HandleSunion(c *Client, args []string) error {
+	return setAlgebraGeneric(c, args[1:], "", setUnion)
+}
+
+func HandleSunionstore(c *Client, args []string) error {
+	return setAlgebraGeneric(c, args[2:], args[1], setUnion)
+}
+
+func HandleSdiff(c *Client, args []string) error {
+	return setAlgebraGeneric(c, args[1:], "", setDiff)
+}
+
+func HandleSdiffstore(c *Client, args []string) error {
+	return setAlgebraGeneric(c, args[2:], args[1], setDiff)
+}
+
+// setAlgebraGeneric replies with op applied to the sets at keys or, when
+// there's a destination, stores the result there and replies with its
+// size.
+func setAlgebraGeneric(c *Client, keys []string, destination string, op func(sets []*core.Set) *core.Set) error {
+	if destination == "" {
+		mu.RLock()
+		defer mu.RUnlock()
+	} else {
+		mu.Lock()
+		defer mu.Unlock()
+	}
+
+	sets, err := lookupSets(keys)
+	if err != nil {
+		return err
+	}
+	result := op(sets)
+
+	if destination == "" {
+		replySet(c, result.Members())
+		return nil
+	}
+	storeSet(destination, result)
+	c.Reply.Integer(int64(result.Len()))
+	return nil
+}
+
+// setUnion merges sets, nil standing for an empty one.
+func setUnion(sets []*core.Set) *core.Set {
+	result := core.NewSet()
+	for _, set := range sets {
+		if set == nil {
+			continue
+		}
+		set.Range(func(member string) bool {
+			result.Add(member)
+			return true
+		})
+	}
+	return result
+}
+
+// setDiff returns the members of the first set that are in none of the
+// others, nil standing for an empty set.
+func setDiff(sets []*core.Set) *core.Set {
+	result := core.NewSet()
+	if sets[0] == nil {
+		return result
+	}
+	sets[0].Range(func(member string) bool {
+		for _, other := range sets[1:] {
+			if other != nil && other.Contains(member) {
+				return true
+			}
+		}
+		result.Add(member)
+		return true
+	})
+	return result
+}
@@ -0,0 +1,18 @@
+package commands
+
+// HandleTouch counts the given keys that exist. We don't track access
+// times, so there is nothing else to update.
+func HandleTouch(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	count := 0
+	for _, key := range args[1:] {
+		if _, exists := lookupKeyRead(key); exists {
+			count++
+		}
+	}
+
+	c.Reply.Integer(int64(count))
+	return nil
+}
@@ -0,0 +1,51 @@
+package commands
+
+import "time"
+
+func HandleTTL(c *Client, args []string) error {
+	return ttlGeneric(c, args[1], false, false)
+}
+
+func HandlePTTL(c *Client, args []string) error {
+	return ttlGeneric(c, args[1], true, false)
+}
+
+func HandleExpireTime(c *Client, args []string) error {
+	return ttlGeneric(c, args[1], false, true)
+}
+
+func HandlePexpireTime(c *Client, args []string) error {
+	return ttlGeneric(c, args[1], true, true)
+}
+
+// ttlGeneric replies with the remaining TTL of key, or its absolute unix
+// deadline, in seconds (rounded) or ms: -2 if the key doesn't exist, -1 if
+// it has no TTL.
+func ttlGeneric(c *Client, key string, outputMs, absolute bool) error {
+	mu.RLock()
+	entry, exists := lookupKeyRead(key)
+	mu.RUnlock()
+
+	if !exists {
+		c.Reply.Integer(-2)
+		return nil
+	}
+	if entry.ExpiresAt == 0 {
+		c.Reply.Integer(-1)
+		return nil
+	}
+
+	ttl := entry.ExpiresAt
+	if !absolute {
+		ttl -= time.Now().UnixMilli()
+	}
+	if ttl < 0 {
+		ttl = 0
+	}
+	if !outputMs {
+		ttl = (ttl + 500) / 1000
+	}
+
+	c.Reply.Integer(ttl)
+	return nil
+}
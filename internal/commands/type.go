@@ -1,31 +1,29 @@
 package commands
 
-import (
-	"net"
-
-	"github.com/codecrafters-io/redis-starter-go/internal/utils"
-)
-
-func HandleType(conn net.Conn, args []string) {
-	if len(args) < 2 {
-		utils.SendError(conn, "wrong number of arguments for 'TYPE' command")
-		return
-	}
-
+func HandleType(c *Client, args []string) error {
 	key := args[1]
 	entry, exists := GetEntry(key)
 
-	response := "+none\r\n"
+	response := "none"
 	if exists {
 		switch entry.Type {
 		case "stream":
-			response = "+stream\r\n"
+			response = "stream"
 		case "string":
-			response = "+string\r\n"
+			response = "string"
+		case "list":
+			response = "list"
+		case "hash":
+			response = "hash"
+		case "set":
+			response = "set"
+		case "zset":
+			response = "zset"
 		default:
-			response = "+none\r\n"
+			response = "none"
 		}
 	}
 
-	conn.Write([]byte(response))
+	c.Reply.SimpleString(response)
+	return nil
 }
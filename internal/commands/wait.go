@@ -1,39 +1,37 @@
 package commands
 
 import (
-	"fmt"
-	"net"
+	"errors"
 	"strconv"
 	"time"
 
 	"github.com/codecrafters-io/redis-starter-go/internal/replication"
 )
 
-func HandleWait(conn net.Conn, args []string, isReplica bool) {
-	if isReplica {
-		return
-	}
-	if len(args) < 3 {
-		conn.Write([]byte("-ERR wrong number of arguments for 'WAIT' command\r\n"))
-		return
-	}
+func HandleWait(c *Client, args []string) error {
 	numReplicas, err := strconv.Atoi(args[1])
 	if err != nil {
-		conn.Write([]byte("-ERR invalid numreplicas\r\n"))
-		return
+		return errors.New("ERR invalid numreplicas")
 	}
 	timeoutMs, err := strconv.Atoi(args[2])
 	if err != nil {
-		conn.Write([]byte("-ERR invalid timeout\r\n"))
-		return
+		return errors.New("ERR invalid timeout")
 	}
 
 	desiredOffset := replication.GetOffset()
 	if replication.GetReplicaCount() == 0 {
-		conn.Write([]byte(":0\r\n"))
-		return
+		c.Reply.Integer(0)
+		return nil
+	}
+	// inside a transaction, which holds execMu for writing and is halfway
+	// through its reply, just say how many replicas are caught up already
+	if c.InExec {
+		c.Reply.Integer(int64(replication.CountReplicasAtOrAboveOffset(desiredOffset)))
+		return nil
 	}
 
+	// nothing else will be written until the replicas answer
+	c.Reply.Flush()
 	replication.RequestAckFromReplicas()
 
 	deadline := time.Now().Add(time.Duration(timeoutMs) * time.Millisecond)
@@ -49,5 +47,6 @@ func HandleWait(conn net.Conn, args []string, isReplica bool) {
 		time.Sleep(10 * time.Millisecond)
 	}
 
-	conn.Write([]byte(fmt.Sprintf(":%d\r\n", count)))
+	c.Reply.Integer(int64(count))
+	return nil
 }
@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"errors"
+	"time"
+
+	models "github.com/codecrafters-io/redis-starter-go/internal/models/core"
+)
+
+// Optimistic locking: a client WATCHes keys before its MULTI, and its EXEC
+// fails, replying nil, if any of them was modified in between. Everything
+// that modifies a key calls signalModifiedKey, which marks the clients
+// watching it as dirty. setKey and deleteKey do so, which covers most
+// commands, expiry and the writes a replica gets from its master; handlers
+// that modify a value in place call it themselves.
+//
+// Like the keyspace, watchedKeys and the watch state of clients are
+// guarded by mu.
+
+// watchedKeys maps each watched key to the clients watching it, and
+// whether it had already expired when they did.
+var watchedKeys = make(map[string]map[*Client]bool)
+
+// signalModifiedKey notes that key was modified. Callers must hold the
+// write lock on mu.
+func signalModifiedKey(key string) {
+	clients := watchedKeys[key]
+	for c, expired := range clients {
+		// deleting a key that had expired when it was watched isn't a
+		// change: it was missing then, and still is
+		if expired {
+			if _, exists := store.Get(key); !exists {
+				clients[c] = false
+				continue
+			}
+		}
+		c.DirtyCAS = true
+		unwatchAllKeys(c) // no need to hear about it again
+	}
+}
+
+// signalFlushedKeys is signalModifiedKey for the keys about to be removed
+// by a FLUSHALL. Callers must hold the write lock on mu.
+func signalFlushedKeys() {
+	for key, clients := range watchedKeys {
+		if _, exists := store.Get(key); !exists {
+			continue
+		}
+		for c, expired := range clients {
+			if expired {
+				clients[c] = false
+				continue
+			}
+			c.DirtyCAS = true
+			unwatchAllKeys(c)
+		}
+	}
+}
+
+func watchKey(c *Client, key string) {
+	clients := watchedKeys[key]
+	if _, watched := clients[c]; watched {
+		return
+	}
+	if clients == nil {
+		clients = make(map[*Client]bool)
+		watchedKeys[key] = clients
+	}
+	entry, exists := store.Get(key)
+	clients[c] = exists && isExpired(entry, time.Now().UnixMilli())
+	c.WatchedKeys = append(c.WatchedKeys, key)
+}
+
+// unwatchAllKeys forgets the keys c watches. Callers must hold the write
+// lock on mu.
+func unwatchAllKeys(c *Client) {
+	for _, key := range c.WatchedKeys {
+		delete(watchedKeys[key], c)
+		if len(watchedKeys[key]) == 0 {
+			delete(watchedKeys, key)
+		}
+	}
+	c.WatchedKeys = nil
+}
+
+// watchedKeyExpired reports whether one of the keys c watches has expired
+// since, which counts as a modification even if it wasn't deleted yet.
+// Callers must hold mu.
+func watchedKeyExpired(c *Client) bool {
+	now := time.Now().UnixMilli()
+	for _, key := range c.WatchedKeys {
+		if watchedKeys[key][c] {
+			continue // it had already expired
+		}
+		if entry, exists := store.Get(key); exists && isExpired(entry, now) {
+			return true
+		}
+	}
+	return false
+}
+
+// HandleWatch watches keys for the next transaction: WATCH key [key ...].
+func HandleWatch(c *Client, args []string) error {
+	models.ClientMu.Lock()
+	inTransaction := c.InTransaction
+	models.ClientMu.Unlock()
+	if inTransaction {
+		return errors.New("ERR WATCH inside MULTI is not allowed")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// no point watching more keys once the transaction is bound to fail
+	if !c.DirtyCAS {
+		for _, key := range args[1:] {
+			watchKey(c, key)
+		}
+	}
+	c.Reply.OK()
+	return nil
+}
+
+// HandleUnwatch forgets all the watched keys: UNWATCH.
+func HandleUnwatch(c *Client, args []string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	unwatchAllKeys(c)
+	c.DirtyCAS = false
+	c.Reply.OK()
+	return nil
+}
+
+// FreeClient releases what the keyspace holds for a closed connection.
+func FreeClient(c *Client) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	unwatchAllKeys(c)
+}
@@ -1,8 +1,8 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
-	"net"
 	"strconv"
 	"strings"
 	"time"
@@ -10,19 +10,13 @@ import (
 	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
 )
 
-func HandleXadd(conn net.Conn, args []string) {
-	if len(args) < 4 {
-		conn.Write([]byte("-ERR wrong number of arguments for 'XADD' command\r\n"))
-		return
-	}
-
+func HandleXadd(c *Client, args []string) error {
 	streamKey := args[1]
 	entryID := args[2]
 	fields := args[3:]
 
 	if len(fields)%2 != 0 {
-		conn.Write([]byte("-ERR wrong number of arguments for fields\r\n"))
-		return
+		return errors.New("ERR wrong number of arguments for 'xadd' command")
 	}
 
 	fieldMap := make(map[string]string)
@@ -35,13 +29,12 @@ func HandleXadd(conn net.Conn, args []string) {
 	mu.Lock()
 	defer mu.Unlock()
 
-	entry, exists := store[streamKey]
+	entry, exists := lookupKeyWrite(streamKey)
 	var stream core.Stream
 
 	if exists {
 		if entry.Type != "stream" {
-			conn.Write([]byte("-ERR key exists and is not a stream\r\n"))
-			return
+			return errors.New("ERR key exists and is not a stream")
 		}
 		stream = entry.Data.(core.Stream)
 	} else {
@@ -54,8 +47,7 @@ func HandleXadd(conn net.Conn, args []string) {
 		millisPart := strings.TrimSuffix(entryID, "-*")
 		millis, err := strconv.ParseInt(millisPart, 10, 64)
 		if err != nil {
-			conn.Write([]byte("-ERR invalid milliseconds part\r\n"))
-			return
+			return errors.New("ERR invalid milliseconds part")
 		}
 
 		autoID := generateAutoID(stream, &millis)
@@ -70,13 +62,11 @@ func HandleXadd(conn net.Conn, args []string) {
 	if exists && len(stream.Entries) > 0 {
 		lastEntryID := stream.Entries[len(stream.Entries)-1].ID
 		if err := validateID(entryID, lastEntryID); err != nil {
-			conn.Write([]byte(fmt.Sprintf("-%s\r\n", err.Error())))
-			return
+			return err
 		}
 	} else {
 		if err := validateID(entryID, ""); err != nil {
-			conn.Write([]byte(fmt.Sprintf("-%s\r\n", err.Error())))
-			return
+			return err
 		}
 	}
 
@@ -85,70 +75,14 @@ func HandleXadd(conn net.Conn, args []string) {
 		Fields: fieldMap,
 	})
 
-	store[streamKey] = core.StoreEntry{
-		Type: "stream",
-		Data: stream,
-	}
-
-	// Notify waiting clients
-	waitingClientsMu.Lock()
-	clients := waitingClients[streamKey]
-	newClients := make([]*waitingClient, 0, len(clients))
-
-	for _, client := range clients {
-		startID, ok := client.streams[streamKey]
-		if !ok {
-			continue
-		}
-
-		compare, err := compareIDs(entryID, startID)
-		if err != nil || compare <= 0 {
-			newClients = append(newClients, client)
-			continue
-		}
-
-		// Collect entries for all streams the client is waiting on
-		responseEntries := make(map[string][]core.StreamEntry)
-		for sKey, sStartID := range client.streams {
-			entry, exists := store[sKey]
-			if !exists || entry.Type != "stream" {
-				continue
-			}
-
-			stream, ok := entry.Data.(core.Stream)
-			if !ok {
-				continue
-			}
-
-			var entries []core.StreamEntry
-			for _, e := range stream.Entries {
-				if compare, _ := compareIDs(e.ID, sStartID); compare > 0 {
-					entries = append(entries, e)
-				}
-			}
-
-			if len(entries) > 0 {
-				responseEntries[sKey] = entries
-			}
-		}
-
-		if len(responseEntries) > 0 {
-			select {
-			case client.responseCh <- xreadResponse{entries: responseEntries}:
-				// Client notified
-			default:
-				// Could not notify, client may have timed out
-			}
-		} else {
-			newClients = append(newClients, client)
-		}
-	}
-
-	waitingClients[streamKey] = newClients
-	waitingClientsMu.Unlock()
+	setKey(streamKey, core.StoreEntry{
+		Type:      "stream",
+		Data:      stream,
+		ExpiresAt: entry.ExpiresAt,
+	})
 
-	resp := fmt.Sprintf("$%d\r\n%s\r\n", len(entryID), entryID)
-	conn.Write([]byte(resp))
+	c.Reply.Bulk(entryID)
+	return nil
 }
 
 func parseID(id string) (int64, int64, error) {
@@ -1,36 +1,30 @@
 package commands
 
 import (
-	"fmt"
-	"net"
+	"errors"
 	"strings"
 
 	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+	"github.com/codecrafters-io/redis-starter-go/internal/resp"
 )
 
-func HandleXrange(conn net.Conn, args []string) {
-	if len(args) < 4 {
-		conn.Write([]byte("-ERR wrong number of arguments for 'XRANGE' command\r\n"))
-		return
-	}
-
+func HandleXrange(c *Client, args []string) error {
 	streamKey := args[1]
 	startID := args[2]
 	endID := args[3]
 
 	mu.RLock()
-	entry, exists := store[streamKey]
+	entry, exists := lookupKeyRead(streamKey)
 	mu.RUnlock()
 
 	if !exists || entry.Type != "stream" {
-		conn.Write([]byte("*0\r\n")) // returns an empty array if the stream doesn't exist
-		return
+		c.Reply.ArrayLen(0) // returns an empty array if the stream doesn't exist
+		return nil
 	}
 
 	stream, ok := entry.Data.(core.Stream)
 	if !ok {
-		conn.Write([]byte("-ERR invalid stream data\r\n"))
-		return
+		return errors.New("ERR invalid stream data")
 	}
 
 	// Filter entries within the range
@@ -60,29 +54,24 @@ func HandleXrange(conn net.Conn, args []string) {
 		result = append(result, entry)
 	}
 
-	resp := encodeXrangeResponse(result)
-	conn.Write([]byte(resp))
+	writeStreamEntries(c.Reply, result)
+	return nil
 }
 
-func encodeXrangeResponse(entries []core.StreamEntry) string {
-	var resp strings.Builder
-	resp.WriteString(fmt.Sprintf("*%d\r\n", len(entries)))
+// writeStreamEntries writes entries as an array of [id, [field, value, ...]].
+func writeStreamEntries(w *resp.Writer, entries []core.StreamEntry) {
+	w.ArrayLen(len(entries))
 
 	for _, entry := range entries {
-		resp.WriteString(fmt.Sprintf("*2\r\n$%d\r\n%s\r\n", len(entry.ID), entry.ID))
+		w.ArrayLen(2)
+		w.Bulk(entry.ID)
 
-		var fields []string
+		w.ArrayLen(len(entry.Fields) * 2)
 		for key, value := range entry.Fields {
-			fields = append(fields, key, value)
-		}
-
-		resp.WriteString(fmt.Sprintf("*%d\r\n", len(fields)))
-		for _, field := range fields {
-			resp.WriteString(fmt.Sprintf("$%d\r\n%s\r\n", len(field), field))
+			w.Bulk(key)
+			w.Bulk(value)
 		}
 	}
-
-	return resp.String()
 }
 
 // compareIDs compares two IDs and returns:
@@ -1,50 +1,45 @@
 package commands
 
 import (
-	"fmt"
-	"net"
+	"errors"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+	"github.com/codecrafters-io/redis-starter-go/internal/resp"
 )
 
-func HandleXread(conn net.Conn, args []string) {
+func HandleXread(c *Client, args []string) error {
 	var blockTimeoutMillis int64 = -1
 	streamsArgsIndex := 1
 
 	if len(args) >= 2 && strings.ToUpper(args[1]) == "BLOCK" {
 		if len(args) < 5 {
-			conn.Write([]byte("-ERR wrong number of arguments for 'XREAD' command\r\n"))
-			return
+			return errors.New("ERR wrong number of arguments for 'xread' command")
 		}
 
 		timeoutStr := args[2]
 		timeout, err := strconv.ParseInt(timeoutStr, 10, 64)
 		if err != nil {
-			conn.Write([]byte("-ERR invalid timeout value\r\n"))
-			return
+			return errors.New("ERR invalid timeout value")
 		}
 		blockTimeoutMillis = timeout
 
 		if strings.ToUpper(args[3]) != "STREAMS" {
-			conn.Write([]byte("-ERR expected STREAMS keyword\r\n"))
-			return
+			return errors.New("ERR expected STREAMS keyword")
 		}
 		streamsArgsIndex = 4
 	} else {
-		if len(args) < 2 || strings.ToUpper(args[1]) != "STREAMS" {
-			conn.Write([]byte("-ERR wrong number of arguments for 'XREAD' command\r\n"))
-			return
+		if strings.ToUpper(args[1]) != "STREAMS" {
+			return errSyntax
 		}
 		streamsArgsIndex = 2
 	}
 
 	remainingArgs := args[streamsArgsIndex:]
 	if len(remainingArgs)%2 != 0 || len(remainingArgs) == 0 {
-		conn.Write([]byte("-ERR wrong number of arguments for streams and IDs\r\n"))
-		return
+		return errors.New("ERR Unbalanced 'xread' list of streams: for each stream key an ID or '$' must be specified.")
 	}
 
 	numStreams := len(remainingArgs) / 2
@@ -53,31 +48,26 @@ func HandleXread(conn net.Conn, args []string) {
 
 	mu.RLock()
 	responseEntries := make(map[string][]core.StreamEntry)
-	entriesAvailable := false
 	for i, streamKey := range streamKeys {
-		entry, exists := store[streamKey]
-		if !exists || entry.Type != "stream" {
-			continue
-		}
-
-		stream, ok := entry.Data.(core.Stream)
-		if !ok {
-			continue
+		entry, exists := lookupKeyRead(streamKey)
+		var stream core.Stream
+		if exists && entry.Type == "stream" {
+			stream, _ = entry.Data.(core.Stream)
 		}
 
 		startID := startIDs[i]
-		var entries []core.StreamEntry
-
 		if startID == "$" {
-
+			// Only entries added from now on. The ID is resolved in args
+			// so that the retry after we're woken up still means "now".
 			if len(stream.Entries) > 0 {
-				startID = stream.Entries[len(stream.Entries)-1].ID
+				startIDs[i] = stream.Entries[len(stream.Entries)-1].ID
 			} else {
-
-				startID = "0-0"
+				startIDs[i] = "0-0"
 			}
+			continue
 		}
 
+		var entries []core.StreamEntry
 		for _, e := range stream.Entries {
 			compare, err := compareIDs(e.ID, startID)
 			if err == nil && compare > 0 {
@@ -87,134 +77,49 @@ func HandleXread(conn net.Conn, args []string) {
 
 		if len(entries) > 0 {
 			responseEntries[streamKey] = entries
-			entriesAvailable = true
 		}
 	}
 	mu.RUnlock()
 
-	if entriesAvailable || blockTimeoutMillis < 0 {
-		sendXreadResponse(conn, responseEntries)
-		return
+	if len(responseEntries) > 0 || blockTimeoutMillis < 0 {
+		sendXreadResponse(c, streamKeys, responseEntries)
+		return nil
 	}
 
-	// Blocking mode setup
-	wc := &waitingClient{
-		streams:    make(map[string]string),
-		responseCh: make(chan xreadResponse, 1),
-		deadline:   time.Now().Add(time.Duration(blockTimeoutMillis) * time.Millisecond),
-	}
-
-	for i, streamKey := range streamKeys {
-		startID := startIDs[i]
-		if startID == "$" {
-			entry, exists := store[streamKey]
-			if exists && entry.Type == "stream" {
-				stream, ok := entry.Data.(core.Stream)
-				if ok && len(stream.Entries) > 0 {
-					startID = stream.Entries[len(stream.Entries)-1].ID
-				} else {
-					startID = "0-0"
-				}
-			}
-		}
-		wc.streams[streamKey] = startID
-	}
-
-	// Add to waiting clients
-	waitingClientsMu.Lock()
-	for streamKey := range wc.streams {
-		waitingClients[streamKey] = append(waitingClients[streamKey], wc)
-	}
-	waitingClientsMu.Unlock()
-
-	// Wait for response or timeout
-	if blockTimeoutMillis == 0 {
-		// Blocking indefinitely
-		res := <-wc.responseCh
-		sendXreadResponse(conn, res.entries)
-	} else {
-		// Blocking with timeout
-		select {
-		case res := <-wc.responseCh:
-			sendXreadResponse(conn, res.entries)
-		case <-time.After(time.Until(wc.deadline)):
-			mu.RLock()
-			timeoutResponse := make(map[string][]core.StreamEntry)
-			for streamKey, startID := range wc.streams {
-				entry, exists := store[streamKey]
-				if !exists || entry.Type != "stream" {
-					continue
-				}
-
-				stream, ok := entry.Data.(core.Stream)
-				if !ok {
-					continue
-				}
-
-				var entries []core.StreamEntry
-				for _, e := range stream.Entries {
-					compare, _ := compareIDs(e.ID, startID)
-					if compare > 0 {
-						entries = append(entries, e)
-					}
-				}
-
-				if len(entries) > 0 {
-					timeoutResponse[streamKey] = entries
-				}
-			}
-			mu.RUnlock()
-
-			if len(timeoutResponse) > 0 {
-				sendXreadResponse(conn, timeoutResponse)
-			} else {
-				conn.Write([]byte("$-1\r\n"))
-			}
-		}
-	}
-
-	// Cleanup
-	waitingClientsMu.Lock()
-	for streamKey := range wc.streams {
-		for i, client := range waitingClients[streamKey] {
-			if client == wc {
-				waitingClients[streamKey] = append(waitingClients[streamKey][:i], waitingClients[streamKey][i+1:]...)
-				break
-			}
-		}
-	}
-	waitingClientsMu.Unlock()
+	// XREAD BLOCK 0 waits forever
+	return blockForKeys(streamKeys, "stream", time.Duration(blockTimeoutMillis)*time.Millisecond, func(c *Client) {
+		c.Reply.Null()
+	})
 }
 
-func sendXreadResponse(conn net.Conn, entries map[string][]core.StreamEntry) {
+// sendXreadResponse replies with the entries found for each stream, in the
+// order the streams were requested.
+func sendXreadResponse(c *Client, streamKeys []string, entries map[string][]core.StreamEntry) {
 	if len(entries) == 0 {
-		conn.Write([]byte("*-1\r\n"))
+		c.Reply.NullArray()
 		return
 	}
 
-	var resp strings.Builder
-	resp.WriteString(fmt.Sprintf("*%d\r\n", len(entries)))
-
-	for streamKey, streamEntries := range entries {
-		resp.WriteString(fmt.Sprintf("*2\r\n$%d\r\n%s\r\n", len(streamKey), streamKey))
-		resp.WriteString(fmt.Sprintf("*%d\r\n", len(streamEntries)))
-
-		for _, entry := range streamEntries {
-			resp.WriteString(fmt.Sprintf("*2\r\n$%d\r\n%s\r\n", len(entry.ID), entry.ID))
-
-			var fields []string
-			for k, v := range entry.Fields {
-				fields = append(fields, k, v)
-			}
+	// RESP3 clients get a map of stream -> entries, RESP2 ones an array of
+	// [stream, entries] pairs.
+	resp3 := c.Reply.Protocol() >= resp.RESP3
+	if resp3 {
+		c.Reply.MapLen(len(entries))
+	} else {
+		c.Reply.ArrayLen(len(entries))
+	}
 
-			resp.WriteString(fmt.Sprintf("*%d\r\n", len(fields)))
-			for _, f := range fields {
-				resp.WriteString(fmt.Sprintf("$%d\r\n%s\r\n", len(f), f))
-			}
+	for _, streamKey := range streamKeys {
+		streamEntries, ok := entries[streamKey]
+		if !ok {
+			continue
 		}
+		if !resp3 {
+			c.Reply.ArrayLen(2)
+		}
+		c.Reply.Bulk(streamKey)
+		writeStreamEntries(c.Reply, streamEntries)
 	}
-
-	conn.Write([]byte(resp.String()))
 }
 
 // func encodeXreadResponse(response []interface{}) string {
@@ -250,3 +155,22 @@ func sendXreadResponse(conn net.Conn, entries map[string][]core.StreamEntry) {
 
 // 	return resp.String()
 // }
+
+// xreadKeys finds the stream keys of XREAD [BLOCK ms] [COUNT n] STREAMS k1 .. kn id1 .. idn.
+func xreadKeys(args []string) []int {
+	for i := 1; i < len(args); i++ {
+		if strings.ToUpper(args[i]) != "STREAMS" {
+			continue
+		}
+		rest := len(args) - i - 1
+		if rest == 0 || rest%2 != 0 {
+			return nil
+		}
+		positions := make([]int, 0, rest/2)
+		for j := i + 1; j <= i+rest/2; j++ {
+			positions = append(positions, j)
+		}
+		return positions
+	}
+	return nil
+}
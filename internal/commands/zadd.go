@@ -0,0 +1,203 @@
+package commands
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+)
+
+// ZADD flags.
+const (
+	zaddNX = 1 << iota
+	zaddXX
+	zaddGT
+	zaddLT
+	zaddCH
+	zaddIncr
+)
+
+// HandleZadd adds members to a sorted set or updates their score:
+// ZADD key [NX|XX] [GT|LT] [CH] [INCR] score member [score member ...].
+// It replies with the number of members added, or also updated with CH.
+// With INCR it works like ZINCRBY, replying with the new score, or nil if
+// the options prevented the update.
+func HandleZadd(c *Client, args []string) error {
+	flags := 0
+	i := 2
+options:
+	for ; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NX":
+			flags |= zaddNX
+		case "XX":
+			flags |= zaddXX
+		case "GT":
+			flags |= zaddGT
+		case "LT":
+			flags |= zaddLT
+		case "CH":
+			flags |= zaddCH
+		case "INCR":
+			flags |= zaddIncr
+		default:
+			break options
+		}
+	}
+
+	pairs := args[i:]
+	if len(pairs) == 0 || len(pairs)%2 != 0 {
+		return errSyntax
+	}
+	if flags&zaddNX != 0 && flags&zaddXX != 0 {
+		return errors.New("ERR XX and NX options at the same time are not compatible")
+	}
+	if flags&zaddNX != 0 && flags&(zaddGT|zaddLT) != 0 || flags&zaddGT != 0 && flags&zaddLT != 0 {
+		return errors.New("ERR GT, LT, and/or NX options at the same time are not compatible")
+	}
+	if flags&zaddIncr != 0 && len(pairs) > 2 {
+		return errors.New("ERR INCR option supports a single increment-element pair")
+	}
+	scores := make([]float64, len(pairs)/2)
+	for j := range scores {
+		score, err := parseFloat(pairs[j*2])
+		if err != nil {
+			return err
+		}
+		scores[j] = score
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	zset, err := lookupZsetWrite(args[1])
+	if err != nil {
+		return err
+	}
+	if zset == nil {
+		if flags&zaddXX != 0 {
+			preventPropagation(c)
+			if flags&zaddIncr != 0 {
+				c.Reply.Null()
+			} else {
+				c.Reply.Integer(0)
+			}
+			return nil
+		}
+		zset = core.NewSortedSet()
+		setKey(args[1], core.StoreEntry{Type: "zset", Data: zset})
+	}
+
+	added, updated := 0, 0
+	var newScore float64
+	applied := false
+	for j, score := range scores {
+		result, err := zsetAdd(zset, pairs[j*2+1], score, flags)
+		if err != nil {
+			deleteZsetIfEmpty(args[1], zset)
+			return err
+		}
+		switch result {
+		case zaddAdded:
+			added++
+		case zaddUpdated:
+			updated++
+		}
+		if result != zaddIgnored {
+			newScore, _ = zset.Score(pairs[j*2+1])
+			applied = true
+		}
+	}
+	deleteZsetIfEmpty(args[1], zset)
+
+	if added+updated == 0 {
+		preventPropagation(c)
+	} else {
+		signalModifiedKey(args[1])
+		signalKeyAsReady(args[1])
+	}
+	switch {
+	case flags&zaddIncr != 0 && !applied:
+		c.Reply.Null()
+	case flags&zaddIncr != 0:
+		c.Reply.Double(newScore)
+	case flags&zaddCH != 0:
+		c.Reply.Integer(int64(added + updated))
+	default:
+		c.Reply.Integer(int64(added))
+	}
+	return nil
+}
+
+// HandleZincrby increments the score of a member, adding it if needed:
+// ZINCRBY key increment member. It replies with the new score.
+func HandleZincrby(c *Client, args []string) error {
+	increment, err := parseFloat(args[2])
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	zset, err := lookupZsetWrite(args[1])
+	if err != nil {
+		return err
+	}
+	created := zset == nil
+	if created {
+		zset = core.NewSortedSet()
+	}
+	if _, err := zsetAdd(zset, args[3], increment, zaddIncr); err != nil {
+		return err
+	}
+	if created {
+		setKey(args[1], core.StoreEntry{Type: "zset", Data: zset})
+	}
+	signalModifiedKey(args[1])
+	signalKeyAsReady(args[1])
+
+	score, _ := zset.Score(args[3])
+	c.Reply.Double(score)
+	return nil
+}
+
+// What zsetAdd did.
+const (
+	zaddIgnored = iota
+	zaddAdded
+	zaddUpdated
+	zaddUnchanged // the member was there already with that score
+)
+
+// zsetAdd sets the score of member, or increments it with zaddIncr, as
+// allowed by the NX/XX/GT/LT flags.
+func zsetAdd(zset *core.SortedSet, member string, score float64, flags int) (int, error) {
+	current, exists := zset.Score(member)
+	if !exists {
+		if flags&zaddXX != 0 {
+			return zaddIgnored, nil
+		}
+		zset.Add(member, score)
+		return zaddAdded, nil
+	}
+
+	if flags&zaddNX != 0 {
+		return zaddIgnored, nil
+	}
+	if flags&zaddIncr != 0 {
+		score += current
+		if math.IsNaN(score) {
+			return zaddIgnored, errors.New("ERR resulting score is not a number (NaN)")
+		}
+	}
+	if flags&zaddGT != 0 && score <= current || flags&zaddLT != 0 && score >= current {
+		return zaddIgnored, nil
+	}
+	if score == current {
+		return zaddUnchanged, nil
+	}
+	zset.Add(member, score)
+	return zaddUpdated, nil
+}
@@ -0,0 +1,63 @@
+package commands
+
+func HandleZcard(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	zset, err := lookupZsetRead(args[1])
+	if err != nil {
+		return err
+	}
+	n := 0
+	if zset != nil {
+		n = zset.Len()
+	}
+	c.Reply.Integer(int64(n))
+	return nil
+}
+
+// HandleZcount counts the members with a score between min and max:
+// ZCOUNT key min max.
+func HandleZcount(c *Client, args []string) error {
+	r, err := parseScoreRange(args[2], args[3])
+	if err != nil {
+		return err
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	zset, err := lookupZsetRead(args[1])
+	if err != nil {
+		return err
+	}
+	n := 0
+	if zset != nil {
+		n = zset.CountByScore(r)
+	}
+	c.Reply.Integer(int64(n))
+	return nil
+}
+
+// HandleZlexcount counts the members between min and max: ZLEXCOUNT key
+// min max.
+func HandleZlexcount(c *Client, args []string) error {
+	r, err := parseLexRange(args[2], args[3])
+	if err != nil {
+		return err
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	zset, err := lookupZsetRead(args[1])
+	if err != nil {
+		return err
+	}
+	n := 0
+	if zset != nil {
+		n = zset.CountByLex(r)
+	}
+	c.Reply.Integer(int64(n))
+	return nil
+}
@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+	"github.com/codecrafters-io/redis-starter-go/internal/resp"
+)
+
+func HandleZpopmin(c *Client, args []string) error {
+	return zpopGeneric(c, args, false)
+}
+
+func HandleZpopmax(c *Client, args []string) error {
+	return zpopGeneric(c, args, true)
+}
+
+// zpopGeneric implements ZPOPMIN/ZPOPMAX key [count], replying with the
+// members popped and their scores: as pairs to RESP3 clients that gave a
+// count, as a flat array otherwise.
+func zpopGeneric(c *Client, args []string, highest bool) error {
+	if len(args) > 3 {
+		return errSyntax
+	}
+	count := 1
+	if len(args) == 3 {
+		n, err := strconv.Atoi(args[2])
+		if err != nil {
+			return errNotInteger
+		}
+		if n < 0 {
+			return errors.New("ERR value is out of range, must be positive")
+		}
+		count = n
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	zset, err := lookupZsetWrite(args[1])
+	if err != nil {
+		return err
+	}
+	var popped []scoredMember
+	if zset != nil {
+		popped = zsetPop(args[1], zset, highest, count)
+	}
+
+	if len(popped) == 0 {
+		preventPropagation(c)
+	}
+	if len(args) == 3 && c.Reply.Protocol() >= resp.RESP3 {
+		replyScored(c, popped, true)
+		return nil
+	}
+	c.Reply.ArrayLen(len(popped) * 2)
+	for _, m := range popped {
+		c.Reply.Bulk(m.member)
+		c.Reply.Double(m.score)
+	}
+	return nil
+}
+
+func HandleBzpopmin(c *Client, args []string) error {
+	return blockingZpopGeneric(c, args, false)
+}
+
+func HandleBzpopmax(c *Client, args []string) error {
+	return blockingZpopGeneric(c, args, true)
+}
+
+// blockingZpopGeneric implements BZPOPMIN/BZPOPMAX key [key ...] timeout:
+// pop from the first non-empty sorted set, replying with [key, member,
+// score], or wait for one of them to get a member.
+func blockingZpopGeneric(c *Client, args []string, highest bool) error {
+	keys := args[1 : len(args)-1]
+	timeout, err := parseBlockTimeout(args[len(args)-1])
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, key := range keys {
+		zset, err := lookupZsetWrite(key)
+		if err != nil {
+			return err
+		}
+		if zset == nil {
+			continue
+		}
+
+		popped := zsetPop(key, zset, highest, 1)[0]
+		if highest {
+			propagateAs(c, "ZPOPMAX", key)
+		} else {
+			propagateAs(c, "ZPOPMIN", key)
+		}
+		c.Reply.ArrayLen(3)
+		c.Reply.Bulk(key)
+		c.Reply.Bulk(popped.member)
+		c.Reply.Double(popped.score)
+		return nil
+	}
+
+	return blockForKeys(keys, "zset", timeout, func(c *Client) {
+		c.Reply.NullArray()
+	})
+}
+
+// zsetPop pops up to count members with the lowest scores, or the highest
+// ones, deleting key if that empties it.
+func zsetPop(key string, zset *core.SortedSet, highest bool, count int) []scoredMember {
+	popped := make([]scoredMember, 0, min(count, zset.Len()))
+	if count > 0 {
+		zset.RangeByRank(0, min(count, zset.Len())-1, highest, func(member string, score float64) bool {
+			popped = append(popped, scoredMember{member, score})
+			return true
+		})
+	}
+	for _, m := range popped {
+		zset.Remove(m.member)
+	}
+	if len(popped) > 0 {
+		signalModifiedKey(key)
+	}
+	deleteZsetIfEmpty(key, zset)
+	return popped
+}
@@ -0,0 +1,162 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+)
+
+// How a range command picks its members.
+const (
+	zrangeAuto = iota // ZRANGE: by rank unless BYSCORE or BYLEX is given
+	zrangeRank
+	zrangeScore
+	zrangeLex
+)
+
+func HandleZrange(c *Client, args []string) error {
+	return zrangeGeneric(c, args, "", zrangeAuto, false)
+}
+
+func HandleZrangestore(c *Client, args []string) error {
+	return zrangeGeneric(c, args[1:], args[1], zrangeAuto, false)
+}
+
+func HandleZrevrange(c *Client, args []string) error {
+	return zrangeGeneric(c, args, "", zrangeRank, true)
+}
+
+func HandleZrangebyscore(c *Client, args []string) error {
+	return zrangeGeneric(c, args, "", zrangeScore, false)
+}
+
+func HandleZrevrangebyscore(c *Client, args []string) error {
+	return zrangeGeneric(c, args, "", zrangeScore, true)
+}
+
+func HandleZrangebylex(c *Client, args []string) error {
+	return zrangeGeneric(c, args, "", zrangeLex, false)
+}
+
+func HandleZrevrangebylex(c *Client, args []string) error {
+	return zrangeGeneric(c, args, "", zrangeLex, true)
+}
+
+// zrangeGeneric implements all the range commands, which take
+// key min max followed by options: with ZRANGE, BYSCORE or BYLEX and REV
+// choose the kind of range and its direction, which are set by the name of
+// the legacy commands; LIMIT offset count pages through a range by score
+// or by member; WITHSCORES adds the scores to the reply. With a
+// destination (ZRANGESTORE, args starting at the source key) the members
+// are stored there instead, and the reply is how many there were.
+func zrangeGeneric(c *Client, args []string, destination string, rangeType int, reverse bool) error {
+	key, minArg, maxArg := args[1], args[2], args[3]
+	withScores, hasLimit := false, false
+	offset, count := 0, -1
+	unified := rangeType == zrangeAuto
+	for i := 4; i < len(args); i++ {
+		switch option := strings.ToUpper(args[i]); {
+		case option == "WITHSCORES" && destination == "":
+			withScores = true
+		case option == "LIMIT" && i+2 < len(args):
+			var err error
+			if offset, err = parseInt(args[i+1]); err != nil {
+				return err
+			}
+			if count, err = parseInt(args[i+2]); err != nil {
+				return err
+			}
+			hasLimit = true
+			i += 2
+		case option == "BYSCORE" && rangeType == zrangeAuto:
+			rangeType = zrangeScore
+		case option == "BYLEX" && rangeType == zrangeAuto:
+			rangeType = zrangeLex
+		case option == "REV" && unified && !reverse:
+			reverse = true
+		default:
+			return errSyntax
+		}
+	}
+	if rangeType == zrangeAuto {
+		rangeType = zrangeRank
+	}
+
+	if hasLimit && rangeType == zrangeRank {
+		return errors.New("ERR syntax error, LIMIT is only supported in combination with either BYSCORE or BYLEX")
+	}
+	if withScores && rangeType == zrangeLex {
+		return errors.New("ERR syntax error, WITHSCORES not supported in combination with BYLEX")
+	}
+	// a reverse range by score or member is given from max to min
+	if reverse && rangeType != zrangeRank {
+		minArg, maxArg = maxArg, minArg
+	}
+
+	var rangeFn func(zset *core.SortedSet, fn func(string, float64) bool)
+	switch rangeType {
+	case zrangeRank:
+		start, err := parseInt(minArg)
+		if err != nil {
+			return err
+		}
+		stop, err := parseInt(maxArg)
+		if err != nil {
+			return err
+		}
+		rangeFn = func(zset *core.SortedSet, fn func(string, float64) bool) {
+			if start, stop, ok := rankRange(start, stop, zset.Len()); ok {
+				zset.RangeByRank(start, stop, reverse, fn)
+			}
+		}
+	case zrangeScore:
+		r, err := parseScoreRange(minArg, maxArg)
+		if err != nil {
+			return err
+		}
+		rangeFn = func(zset *core.SortedSet, fn func(string, float64) bool) {
+			zset.RangeByScore(r, reverse, offset, count, fn)
+		}
+	case zrangeLex:
+		r, err := parseLexRange(minArg, maxArg)
+		if err != nil {
+			return err
+		}
+		rangeFn = func(zset *core.SortedSet, fn func(string, float64) bool) {
+			zset.RangeByLex(r, reverse, offset, count, fn)
+		}
+	}
+
+	if destination == "" {
+		mu.RLock()
+		defer mu.RUnlock()
+	} else {
+		mu.Lock()
+		defer mu.Unlock()
+	}
+
+	zset, err := lookupZsetRead(key)
+	if err != nil {
+		return err
+	}
+	members := []scoredMember{}
+	if zset != nil && offset >= 0 {
+		rangeFn(zset, func(member string, score float64) bool {
+			members = append(members, scoredMember{member, score})
+			return true
+		})
+	}
+
+	if destination == "" {
+		replyScored(c, members, withScores)
+		return nil
+	}
+	result := core.NewSortedSet()
+	for _, m := range members {
+		result.Add(m.member, m.score)
+	}
+	storeZset(destination, result)
+	c.Reply.Integer(int64(result.Len()))
+	return nil
+}
@@ -0,0 +1,54 @@
+package commands
+
+import "strings"
+
+func HandleZrank(c *Client, args []string) error {
+	return zrankGeneric(c, args, false)
+}
+
+func HandleZrevrank(c *Client, args []string) error {
+	return zrankGeneric(c, args, true)
+}
+
+// zrankGeneric implements ZRANK/ZREVRANK key member [WITHSCORE], replying
+// with the rank of member, from the lowest score or the highest, or with
+// [rank, score].
+func zrankGeneric(c *Client, args []string, reverse bool) error {
+	withScore := false
+	switch {
+	case len(args) == 4 && strings.EqualFold(args[3], "WITHSCORE"):
+		withScore = true
+	case len(args) != 3:
+		return errSyntax
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	zset, err := lookupZsetRead(args[1])
+	if err != nil {
+		return err
+	}
+	rank, ok := 0, false
+	if zset != nil {
+		rank, ok = zset.Rank(args[2], reverse)
+	}
+	if !ok {
+		if withScore {
+			c.Reply.NullArray()
+		} else {
+			c.Reply.Null()
+		}
+		return nil
+	}
+
+	if !withScore {
+		c.Reply.Integer(int64(rank))
+		return nil
+	}
+	score, _ := zset.Score(args[2])
+	c.Reply.ArrayLen(2)
+	c.Reply.Integer(int64(rank))
+	c.Reply.Double(score)
+	return nil
+}
@@ -0,0 +1,105 @@
+package commands
+
+import "github.com/codecrafters-io/redis-starter-go/internal/models/core"
+
+// HandleZrem removes members from a sorted set, replying with how many
+// were there: ZREM key member [member ...].
+func HandleZrem(c *Client, args []string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	zset, err := lookupZsetWrite(args[1])
+	if err != nil {
+		return err
+	}
+	removed := 0
+	if zset != nil {
+		for _, member := range args[2:] {
+			if zset.Remove(member) {
+				removed++
+			}
+		}
+		deleteZsetIfEmpty(args[1], zset)
+	}
+
+	if removed == 0 {
+		preventPropagation(c)
+	} else {
+		signalModifiedKey(args[1])
+	}
+	c.Reply.Integer(int64(removed))
+	return nil
+}
+
+// HandleZremrangebyrank removes the members with a rank between start and
+// stop: ZREMRANGEBYRANK key start stop.
+func HandleZremrangebyrank(c *Client, args []string) error {
+	start, err := parseInt(args[2])
+	if err != nil {
+		return err
+	}
+	stop, err := parseInt(args[3])
+	if err != nil {
+		return err
+	}
+	return zremrangeGeneric(c, args[1], func(zset *core.SortedSet, fn func(string, float64) bool) {
+		if start, stop, ok := rankRange(start, stop, zset.Len()); ok {
+			zset.RangeByRank(start, stop, false, fn)
+		}
+	})
+}
+
+// HandleZremrangebyscore removes the members with a score between min and
+// max: ZREMRANGEBYSCORE key min max.
+func HandleZremrangebyscore(c *Client, args []string) error {
+	r, err := parseScoreRange(args[2], args[3])
+	if err != nil {
+		return err
+	}
+	return zremrangeGeneric(c, args[1], func(zset *core.SortedSet, fn func(string, float64) bool) {
+		zset.RangeByScore(r, false, 0, -1, fn)
+	})
+}
+
+// HandleZremrangebylex removes the members between min and max:
+// ZREMRANGEBYLEX key min max.
+func HandleZremrangebylex(c *Client, args []string) error {
+	r, err := parseLexRange(args[2], args[3])
+	if err != nil {
+		return err
+	}
+	return zremrangeGeneric(c, args[1], func(zset *core.SortedSet, fn func(string, float64) bool) {
+		zset.RangeByLex(r, false, 0, -1, fn)
+	})
+}
+
+// zremrangeGeneric removes the members walked by rangeFn from the sorted
+// set at key, replying with how many there were.
+func zremrangeGeneric(c *Client, key string, rangeFn func(zset *core.SortedSet, fn func(string, float64) bool)) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	zset, err := lookupZsetWrite(key)
+	if err != nil {
+		return err
+	}
+	var members []string
+	if zset != nil {
+		rangeFn(zset, func(member string, _ float64) bool {
+			members = append(members, member)
+			return true
+		})
+		for _, member := range members {
+			zset.Remove(member)
+		}
+		deleteZsetIfEmpty(key, zset)
+	}
+
+	if len(members) == 0 {
+		preventPropagation(c)
+	} else {
+		signalModifiedKey(key)
+	}
+	c.Reply.Integer(int64(len(members)))
+	return nil
+}
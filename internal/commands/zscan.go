@@ -0,0 +1,46 @@
+package commands
+
+import "github.com/codecrafters-io/redis-starter-go/internal/resp"
+
+// HandleZscan iterates over the members of a sorted set and their scores:
+// ZSCAN key cursor [MATCH pattern] [COUNT count].
+func HandleZscan(c *Client, args []string) error {
+	cursor, err := parseScanCursor(args[2])
+	if err != nil {
+		return err
+	}
+	opts, err := parseScanOptions(args[3:], "zset")
+	if err != nil {
+		return err
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	zset, err := lookupZsetRead(args[1])
+	if err != nil {
+		return err
+	}
+	items := []string{}
+	if zset == nil {
+		replyScan(c, 0, items)
+		return nil
+	}
+
+	found := 0
+	for maxIterations := opts.maxIterations(); ; maxIterations-- {
+		cursor = zset.Scan(cursor, func(member string, score float64) {
+			if !opts.matches(member) {
+				return
+			}
+			found++
+			items = append(items, member, resp.FormatDouble(score))
+		})
+		if cursor == 0 || maxIterations <= 1 || found >= opts.count {
+			break
+		}
+	}
+
+	replyScan(c, cursor, items)
+	return nil
+}
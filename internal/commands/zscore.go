@@ -0,0 +1,47 @@
+package commands
+
+func HandleZscore(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	zset, err := lookupZsetRead(args[1])
+	if err != nil {
+		return err
+	}
+	if zset == nil {
+		c.Reply.Null()
+		return nil
+	}
+	score, ok := zset.Score(args[2])
+	if !ok {
+		c.Reply.Null()
+		return nil
+	}
+	c.Reply.Double(score)
+	return nil
+}
+
+// HandleZmscore replies with the score of each member, nil for those that
+// aren't in the sorted set: ZMSCORE key member [member ...].
+func HandleZmscore(c *Client, args []string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	zset, err := lookupZsetRead(args[1])
+	if err != nil {
+		return err
+	}
+	c.Reply.ArrayLen(len(args) - 2)
+	for _, member := range args[2:] {
+		if zset == nil {
+			c.Reply.Null()
+			continue
+		}
+		if score, ok := zset.Score(member); ok {
+			c.Reply.Double(score)
+		} else {
+			c.Reply.Null()
+		}
+	}
+	return nil
+}
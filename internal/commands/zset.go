@@ -0,0 +1,155 @@
+package commands
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+	"github.com/codecrafters-io/redis-starter-go/internal/resp"
+)
+
+// Helpers shared by the sorted set commands. Like the other keyspace
+// helpers they expect the caller to hold mu.
+
+// lookupZsetRead returns the sorted set at key, nil if there is none.
+func lookupZsetRead(key string) (*core.SortedSet, error) {
+	entry, exists := lookupKeyRead(key)
+	return zsetFromEntry(entry, exists)
+}
+
+// lookupZsetWrite is lookupZsetRead for commands that modify the set.
+func lookupZsetWrite(key string) (*core.SortedSet, error) {
+	entry, exists := lookupKeyWrite(key)
+	return zsetFromEntry(entry, exists)
+}
+
+func zsetFromEntry(entry core.StoreEntry, exists bool) (*core.SortedSet, error) {
+	if !exists {
+		return nil, nil
+	}
+	if entry.Type != "zset" {
+		return nil, errWrongType
+	}
+	return entry.Data.(*core.SortedSet), nil
+}
+
+// deleteZsetIfEmpty removes key once its sorted set has no members left.
+func deleteZsetIfEmpty(key string, zset *core.SortedSet) {
+	if zset.Len() == 0 {
+		deleteKey(key)
+	}
+}
+
+// storeZset replaces whatever is at key with zset, the result of one of
+// the *STORE commands; an empty result deletes key.
+func storeZset(key string, zset *core.SortedSet) {
+	if zset.Len() == 0 {
+		deleteKey(key)
+		return
+	}
+	setKey(key, core.StoreEntry{Type: "zset", Data: zset})
+}
+
+// scoredMember is a member with its score, as the range commands collect
+// them.
+type scoredMember struct {
+	member string
+	score  float64
+}
+
+// replyScored writes members, followed by their scores if withScores is
+// set. RESP3 clients get a [member, score] pair for each of them.
+func replyScored(c *Client, members []scoredMember, withScores bool) {
+	if !withScores {
+		c.Reply.ArrayLen(len(members))
+		for _, m := range members {
+			c.Reply.Bulk(m.member)
+		}
+		return
+	}
+
+	resp3 := c.Reply.Protocol() >= resp.RESP3
+	if resp3 {
+		c.Reply.ArrayLen(len(members))
+	} else {
+		c.Reply.ArrayLen(len(members) * 2)
+	}
+	for _, m := range members {
+		if resp3 {
+			c.Reply.ArrayLen(2)
+		}
+		c.Reply.Bulk(m.member)
+		c.Reply.Double(m.score)
+	}
+}
+
+// parseScoreRange parses the min and max of ZRANGEBYSCORE & co.: a score,
+// optionally prefixed by "(" to exclude it, or -inf/+inf.
+func parseScoreRange(minArg, maxArg string) (core.ScoreRange, error) {
+	var r core.ScoreRange
+	var ok1, ok2 bool
+	r.Min, r.MinEx, ok1 = parseScoreBound(minArg)
+	r.Max, r.MaxEx, ok2 = parseScoreBound(maxArg)
+	if !ok1 || !ok2 {
+		return r, errors.New("ERR min or max is not a float")
+	}
+	return r, nil
+}
+
+func parseScoreBound(arg string) (float64, bool, bool) {
+	exclusive := strings.HasPrefix(arg, "(")
+	if exclusive {
+		arg = arg[1:]
+	}
+	score, err := strconv.ParseFloat(arg, 64)
+	if err != nil || math.IsNaN(score) {
+		return 0, false, false
+	}
+	return score, exclusive, true
+}
+
+// parseLexRange parses the min and max of ZRANGEBYLEX & co.: "[" or "("
+// followed by a member, to include or exclude it, or "-" and "+".
+func parseLexRange(minArg, maxArg string) (core.LexRange, error) {
+	var r core.LexRange
+	var ok1, ok2 bool
+	r.Min, ok1 = parseLexBound(minArg)
+	r.Max, ok2 = parseLexBound(maxArg)
+	if !ok1 || !ok2 {
+		return r, errors.New("ERR min or max not valid string range item")
+	}
+	return r, nil
+}
+
+func parseLexBound(arg string) (core.LexBound, bool) {
+	switch {
+	case arg == "-":
+		return core.LexBound{Inf: -1}, true
+	case arg == "+":
+		return core.LexBound{Inf: 1}, true
+	case strings.HasPrefix(arg, "["):
+		return core.LexBound{Value: arg[1:]}, true
+	case strings.HasPrefix(arg, "("):
+		return core.LexBound{Value: arg[1:], Exclusive: true}, true
+	}
+	return core.LexBound{}, false
+}
+
+// rankRange clamps start and stop, which may count from the end when
+// negative, to the ranks of a sorted set of length n. It reports false if
+// the range is empty.
+func rankRange(start, stop, n int) (int, int, bool) {
+	if start < 0 {
+		start += n
+	}
+	if stop < 0 {
+		stop += n
+	}
+	start = max(start, 0)
+	if start > stop || start >= n {
+		return 0, 0, false
+	}
+	return start, min(stop, n-1), true
+}
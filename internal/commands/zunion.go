@@ -0,0 +1,277 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
+)
+
+func HandleZunion(c *Client, args []string) error {
+	return zsetAlgebraGeneric(c, args, 1, zsetUnion)
+}
+
+func HandleZunionstore(c *Client, args []string) error {
+	return zsetAlgebraGeneric(c, args, 2, zsetUnion)
+}
+
+func HandleZinter(c *Client, args []string) error {
+	return zsetAlgebraGeneric(c, args, 1, zsetInter)
+}
+
+func HandleZinterstore(c *Client, args []string) error {
+	return zsetAlgebraGeneric(c, args, 2, zsetInter)
+}
+
+func HandleZdiff(c *Client, args []string) error {
+	return zsetAlgebraGeneric(c, args, 1, zsetDiff)
+}
+
+func HandleZdiffstore(c *Client, args []string) error {
+	return zsetAlgebraGeneric(c, args, 2, zsetDiff)
+}
+
+// zsetInput is a source of ZUNION & co.: a sorted set, or a plain set
+// whose members all score 1, with its weight.
+type zsetInput struct {
+	zset   *core.SortedSet
+	set    *core.Set
+	weight float64
+}
+
+func (in zsetInput) len() int {
+	switch {
+	case in.zset != nil:
+		return in.zset.Len()
+	case in.set != nil:
+		return in.set.Len()
+	}
+	return 0
+}
+
+func (in zsetInput) score(member string) (float64, bool) {
+	switch {
+	case in.zset != nil:
+		return in.zset.Score(member)
+	case in.set != nil:
+		return 1, in.set.Contains(member)
+	}
+	return 0, false
+}
+
+func (in zsetInput) rangeMembers(fn func(member string, score float64)) {
+	switch {
+	case in.zset != nil:
+		in.zset.Range(func(member string, score float64) bool {
+			fn(member, score)
+			return true
+		})
+	case in.set != nil:
+		in.set.Range(func(member string) bool {
+			fn(member, 1)
+			return true
+		})
+	}
+}
+
+// weighted scales score by the weight of the input; an infinite score
+// weighted by 0 is 0.
+func (in zsetInput) weighted(score float64) float64 {
+	score *= in.weight
+	if math.IsNaN(score) {
+		return 0
+	}
+	return score
+}
+
+// zsetAggregate combines the scores a member has in several inputs.
+type zsetAggregate func(a, b float64) float64
+
+func aggregateSum(a, b float64) float64 {
+	if sum := a + b; !math.IsNaN(sum) {
+		return sum
+	}
+	return 0 // +inf + -inf
+}
+
+// zsetAlgebraGeneric implements ZUNION/ZINTER/ZDIFF numkeys key [key ...]
+// and their *STORE variants, which take a destination before numkeys.
+// ZUNION and ZINTER take [WEIGHTS weight ...] and [AGGREGATE SUM|MIN|MAX];
+// without a destination they all take [WITHSCORES]. With a destination
+// the result is stored there and the reply is its size.
+func zsetAlgebraGeneric(c *Client, args []string, numkeysIdx int, op func(inputs []zsetInput, aggregate zsetAggregate) *core.SortedSet) error {
+	name := strings.ToLower(args[0])
+	destination := ""
+	if numkeysIdx == 2 {
+		destination = args[1]
+	}
+	numkeys, err := strconv.Atoi(args[numkeysIdx])
+	if err != nil {
+		return errNotInteger
+	}
+	if numkeys < 1 {
+		return fmt.Errorf("ERR at least 1 input key is needed for '%s' command", name)
+	}
+	if numkeys > len(args)-numkeysIdx-1 {
+		return errSyntax
+	}
+	keys := args[numkeysIdx+1 : numkeysIdx+1+numkeys]
+
+	isDiff := strings.HasPrefix(name, "zdiff")
+	weights := make([]float64, numkeys)
+	for i := range weights {
+		weights[i] = 1
+	}
+	aggregate := zsetAggregate(aggregateSum)
+	withScores := false
+	for i := numkeysIdx + 1 + numkeys; i < len(args); i++ {
+		switch option := strings.ToUpper(args[i]); {
+		case option == "WEIGHTS" && !isDiff && i+numkeys < len(args):
+			for j := range weights {
+				weight, err := strconv.ParseFloat(args[i+1+j], 64)
+				if err != nil || math.IsNaN(weight) {
+					return errors.New("ERR weight value is not a float")
+				}
+				weights[j] = weight
+			}
+			i += numkeys
+		case option == "AGGREGATE" && !isDiff && i+1 < len(args):
+			i++
+			switch strings.ToUpper(args[i]) {
+			case "SUM":
+				aggregate = aggregateSum
+			case "MIN":
+				aggregate = math.Min
+			case "MAX":
+				aggregate = math.Max
+			default:
+				return errSyntax
+			}
+		case option == "WITHSCORES" && destination == "":
+			withScores = true
+		default:
+			return errSyntax
+		}
+	}
+
+	if destination == "" {
+		mu.RLock()
+		defer mu.RUnlock()
+	} else {
+		mu.Lock()
+		defer mu.Unlock()
+	}
+
+	inputs := make([]zsetInput, numkeys)
+	for i, key := range keys {
+		inputs[i].weight = weights[i]
+		entry, exists := lookupKeyRead(key)
+		if !exists {
+			continue
+		}
+		switch v := entry.Data.(type) {
+		case *core.SortedSet:
+			inputs[i].zset = v
+		case *core.Set:
+			inputs[i].set = v
+		default:
+			return errWrongType
+		}
+	}
+	result := op(inputs, aggregate)
+
+	if destination == "" {
+		members := make([]scoredMember, 0, result.Len())
+		result.Range(func(member string, score float64) bool {
+			members = append(members, scoredMember{member, score})
+			return true
+		})
+		replyScored(c, members, withScores)
+		return nil
+	}
+	storeZset(destination, result)
+	c.Reply.Integer(int64(result.Len()))
+	return nil
+}
+
+// zsetUnion merges the inputs, aggregating the scores of the members that
+// are in several of them.
+func zsetUnion(inputs []zsetInput, aggregate zsetAggregate) *core.SortedSet {
+	scores := make(map[string]float64)
+	for _, in := range inputs {
+		in.rangeMembers(func(member string, score float64) {
+			score = in.weighted(score)
+			if current, ok := scores[member]; ok {
+				score = aggregate(current, score)
+			}
+			scores[member] = score
+		})
+	}
+
+	result := core.NewSortedSet()
+	for member, score := range scores {
+		result.Add(member, score)
+	}
+	return result
+}
+
+// zsetInter keeps the members that are in all the inputs. It walks the
+// smallest one and looks its members up in the others.
+func zsetInter(inputs []zsetInput, aggregate zsetAggregate) *core.SortedSet {
+	result := core.NewSortedSet()
+	inputs = slices.Clone(inputs)
+	slices.SortStableFunc(inputs, func(a, b zsetInput) int {
+		return a.len() - b.len()
+	})
+	if inputs[0].len() == 0 {
+		return result
+	}
+
+	inputs[0].rangeMembers(func(member string, score float64) {
+		score = inputs[0].weighted(score)
+		for _, other := range inputs[1:] {
+			otherScore, ok := other.score(member)
+			if !ok {
+				return
+			}
+			score = aggregate(score, other.weighted(otherScore))
+		}
+		result.Add(member, score)
+	})
+	return result
+}
+
+// zsetDiff keeps the members of the first input that are in none of the
+// others, with their scores.
+func zsetDiff(inputs []zsetInput, _ zsetAggregate) *core.SortedSet {
+	result := core.NewSortedSet()
+	inputs[0].rangeMembers(func(member string, score float64) {
+		for _, other := range inputs[1:] {
+			if _, ok := other.score(member); ok {
+				return
+			}
+		}
+		result.Add(member, score)
+	})
+	return result
+}
+
+// zsetAlgebraKeys finds the keys of ZUNION, ZINTER and ZDIFF for COMMAND
+// GETKEYS.
+func zsetAlgebraKeys(args []string) []int {
+	return numkeysKeys(args, 1)
+}
+
+// zsetAlgebraStoreKeys is zsetAlgebraKeys for the *STORE variants, whose
+// destination comes first.
+func zsetAlgebraStoreKeys(args []string) []int {
+	keys := numkeysKeys(args, 2)
+	if keys == nil {
+		return nil
+	}
+	return append([]int{1}, keys...)
+}
@@ -2,27 +2,78 @@ package handler
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"net"
 
 	"github.com/codecrafters-io/redis-starter-go/internal/commands"
+	models "github.com/codecrafters-io/redis-starter-go/internal/models/core"
 	parser "github.com/codecrafters-io/redis-starter-go/internal/parser"
 )
 
+type request struct {
+	args []string
+	err  error
+}
+
 func HandleConnection(conn net.Conn) {
 	defer conn.Close()
+
+	client := models.RegisterClient(conn)
+	defer models.UnregisterClient(conn)
+	defer commands.FreeClient(client)
+
+	// Requests are read by their own goroutine, so that while a command
+	// is blocked (BLPOP & co.) we still find out if the client goes away.
+	requests := make(chan request, 64)
+	done := make(chan struct{})
+	defer close(done)
+	go readRequests(conn, client, requests, done)
+
+	for req := range requests {
+		if req.err != nil {
+			var protoErr *parser.ProtocolError
+			if errors.As(req.err, &protoErr) {
+				client.Reply.Error("ERR " + protoErr.Error())
+			}
+			client.Reply.Flush()
+			fmt.Println("Error parsing request:", req.err)
+			return
+		}
+		if len(req.args) > 0 {
+			commands.Process(client, req.args, false)
+		}
+
+		// Replies to pipelined commands are batched: only write once every
+		// request read so far has been processed.
+		if len(requests) == 0 {
+			if err := client.Reply.Flush(); err != nil {
+				fmt.Println("Error writing reply:", err)
+				return
+			}
+		}
+	}
+}
+
+// readRequests parses requests off conn until it fails, which includes the
+// client closing the connection; that failure is the last request sent.
+func readRequests(conn net.Conn, client *models.ClientState, requests chan<- request, done <-chan struct{}) {
+	defer close(requests)
 	reader := bufio.NewReader(conn)
 
 	for {
 		args, _, err := parser.ParseRequestWithByteCount(reader)
 		if err != nil {
-			fmt.Println("Error parsing request:", err)
+			close(client.Closed)
+		}
+
+		select {
+		case requests <- request{args: args, err: err}:
+		case <-done:
 			return
 		}
-		if len(args) == 0 {
-			continue
+		if err != nil {
+			return
 		}
-		commands.ProcessCommand(conn, args, false)
-
 	}
 }
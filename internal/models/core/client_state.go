@@ -3,14 +3,90 @@ package core
 import (
 	"net"
 	"sync"
+	"sync/atomic"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/resp"
 )
 
+// ClientState is everything the server tracks about one connection. Reply
+// buffers the replies to the client (and knows which RESP version it
+// negotiated); it is only used by the connection's own goroutine. The other
+// fields are guarded by ClientMu.
 type ClientState struct {
+	ID            int64
+	Conn          net.Conn
+	Reply         *resp.Writer
+	Name          string
+	Authenticated bool
+
+	// Closed is closed once the connection has gone away, which is how a
+	// client blocked in BLPOP & co. notices.
+	Closed chan struct{}
+	// InExec is set while EXEC runs the queued commands, which must not
+	// block.
+	InExec bool
+	// Master is set on the link to our master, whose commands are applied
+	// silently and never propagated.
+	Master bool
+
+	// Propagate, when a handler sets it, replaces the running command in
+	// the replication stream; an empty non-nil slice propagates nothing.
+	// Like Reply it's only touched by the connection's own goroutine.
+	Propagate [][]string
+
 	InTransaction bool
 	CommandQueue  [][]string
+	// DirtyExec is set when a command is rejected while queueing, which
+	// makes the EXEC fail with EXECABORT.
+	DirtyExec bool
+
+	// WatchedKeys are the keys the client WATCHes, and DirtyCAS is set
+	// once one of them is modified, which makes its EXEC fail. Unlike the
+	// fields above they are guarded by the keyspace lock, which whoever
+	// modifies a key already holds.
+	WatchedKeys []string
+	DirtyCAS    bool
 }
 
 var (
 	ClientStates = make(map[net.Conn]*ClientState)
 	ClientMu     sync.Mutex
+
+	nextClientID atomic.Int64
 )
+
+// RegisterClient creates the state for a newly accepted connection.
+func RegisterClient(conn net.Conn) *ClientState {
+	state := &ClientState{
+		ID:     nextClientID.Add(1),
+		Conn:   conn,
+		Reply:  resp.NewWriter(conn),
+		Closed: make(chan struct{}),
+	}
+
+	ClientMu.Lock()
+	ClientStates[conn] = state
+	ClientMu.Unlock()
+	return state
+}
+
+// LookupClient returns the state registered for conn, registering it if
+// the connection wasn't accepted by our listener (e.g. the link to our
+// master).
+func LookupClient(conn net.Conn) *ClientState {
+	ClientMu.Lock()
+	state, exists := ClientStates[conn]
+	ClientMu.Unlock()
+
+	if exists {
+		return state
+	}
+	return RegisterClient(conn)
+}
+
+// UnregisterClient drops the state of a closed connection.
+func UnregisterClient(conn net.Conn) {
+	ClientMu.Lock()
+	delete(ClientStates, conn)
+	ClientMu.Unlock()
+}
@@ -0,0 +1,305 @@
+package core
+
+import (
+	"hash/maphash"
+	"math/bits"
+	"math/rand/v2"
+)
+
+const (
+	dictInitialSize = 4
+	dictMinFill     = 10 // % of buckets in use below which the table shrinks
+)
+
+// Dict is a hash table keyed by strings, modelled on Redis's dict.c. It
+// exists, instead of a plain map, for Scan: a cursor that visits every key
+// present for the whole iteration even while the table grows, shrinks or
+// is being rehashed between calls.
+//
+// The table resizes incrementally: while rehashing there are two tables
+// and every write moves one bucket from the old one to the new one. Reads
+// never modify the Dict, so they may run concurrently with each other.
+type Dict[V any] struct {
+	seed      maphash.Seed
+	tables    [2][]*dictEntry[V]
+	used      [2]int
+	rehashIdx int // next bucket of tables[0] to move, -1 when not rehashing
+}
+
+type dictEntry[V any] struct {
+	key   string
+	value V
+	next  *dictEntry[V]
+}
+
+func NewDict[V any]() *Dict[V] {
+	return &Dict[V]{seed: maphash.MakeSeed(), rehashIdx: -1}
+}
+
+func (d *Dict[V]) Len() int {
+	return d.used[0] + d.used[1]
+}
+
+func (d *Dict[V]) Get(key string) (V, bool) {
+	if e := d.find(key); e != nil {
+		return e.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Set adds key or replaces its value.
+func (d *Dict[V]) Set(key string, value V) {
+	d.rehashStep()
+	if e := d.find(key); e != nil {
+		e.value = value
+		return
+	}
+
+	d.expandIfNeeded()
+	t := 0
+	if d.rehashing() {
+		t = 1
+	}
+	idx := d.hash(key) & d.mask(t)
+	d.tables[t][idx] = &dictEntry[V]{key: key, value: value, next: d.tables[t][idx]}
+	d.used[t]++
+}
+
+// Delete removes key, reporting whether it was there.
+func (d *Dict[V]) Delete(key string) bool {
+	d.rehashStep()
+	h := d.hash(key)
+	for t := 0; t < 2; t++ {
+		if len(d.tables[t]) == 0 {
+			continue
+		}
+		idx := h & d.mask(t)
+		for prev, e := (*dictEntry[V])(nil), d.tables[t][idx]; e != nil; prev, e = e, e.next {
+			if e.key != key {
+				continue
+			}
+			if prev == nil {
+				d.tables[t][idx] = e.next
+			} else {
+				prev.next = e.next
+			}
+			d.used[t]--
+			d.shrinkIfNeeded()
+			return true
+		}
+		if !d.rehashing() {
+			break
+		}
+	}
+	return false
+}
+
+// Range calls fn for every entry until it returns false. fn must not
+// modify the Dict.
+func (d *Dict[V]) Range(fn func(key string, value V) bool) {
+	for t := 0; t < 2; t++ {
+		for _, e := range d.tables[t] {
+			for ; e != nil; e = e.next {
+				if !fn(e.key, e.value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// RandomKey returns a key picked at random, false if the Dict is empty.
+func (d *Dict[V]) RandomKey() (string, bool) {
+	if d.Len() == 0 {
+		return "", false
+	}
+
+	var bucket *dictEntry[V]
+	for bucket == nil {
+		// buckets of the old table below rehashIdx are known to be empty
+		lo := 0
+		if d.rehashing() {
+			lo = d.rehashIdx
+		}
+		i := lo + rand.IntN(len(d.tables[0])+len(d.tables[1])-lo)
+		if i < len(d.tables[0]) {
+			bucket = d.tables[0][i]
+		} else {
+			bucket = d.tables[1][i-len(d.tables[0])]
+		}
+	}
+
+	n := 0
+	for e := bucket; e != nil; e = e.next {
+		n++
+	}
+	e := bucket
+	for i := rand.IntN(n); i > 0; i-- {
+		e = e.next
+	}
+	return e.key, true
+}
+
+// Scan calls fn for the entries of one bucket (two while rehashing) and
+// returns the cursor to continue from, 0 once the whole Dict was visited.
+// Start with cursor 0.
+//
+// The cursor counts with its bits reversed, so it walks the buckets in an
+// order where the buckets a key can move to when the table is resized are
+// still ahead of the cursor. Every key present for the whole scan is
+// returned at least once; keys may be returned more than once.
+func (d *Dict[V]) Scan(cursor uint64, fn func(key string, value V)) uint64 {
+	if d.Len() == 0 {
+		return 0
+	}
+
+	if !d.rehashing() {
+		m0 := d.mask(0)
+		d.scanBucket(0, cursor&m0, fn)
+		return nextCursor(cursor, m0)
+	}
+
+	// visit the small table's bucket, then every bucket of the large one
+	// that its keys expand to
+	small, large := 0, 1
+	if len(d.tables[0]) > len(d.tables[1]) {
+		small, large = 1, 0
+	}
+	m0, m1 := d.mask(small), d.mask(large)
+	d.scanBucket(small, cursor&m0, fn)
+	for {
+		d.scanBucket(large, cursor&m1, fn)
+		cursor = nextCursor(cursor, m1)
+		if cursor&(m0^m1) == 0 {
+			break
+		}
+	}
+	return cursor
+}
+
+func (d *Dict[V]) scanBucket(t int, idx uint64, fn func(key string, value V)) {
+	for e := d.tables[t][idx]; e != nil; e = e.next {
+		fn(e.key, e.value)
+	}
+}
+
+// nextCursor increments the bits of cursor covered by mask in reverse
+// order; the bits above the mask are set first so the carry runs through
+// them.
+func nextCursor(cursor, mask uint64) uint64 {
+	cursor |= ^mask
+	cursor = bits.Reverse64(cursor)
+	cursor++
+	return bits.Reverse64(cursor)
+}
+
+func (d *Dict[V]) find(key string) *dictEntry[V] {
+	if d.Len() == 0 {
+		return nil
+	}
+	h := d.hash(key)
+	for t := 0; t < 2; t++ {
+		if len(d.tables[t]) == 0 {
+			continue
+		}
+		for e := d.tables[t][h&d.mask(t)]; e != nil; e = e.next {
+			if e.key == key {
+				return e
+			}
+		}
+		if !d.rehashing() {
+			break
+		}
+	}
+	return nil
+}
+
+func (d *Dict[V]) hash(key string) uint64 {
+	return maphash.String(d.seed, key)
+}
+
+func (d *Dict[V]) mask(t int) uint64 {
+	return uint64(len(d.tables[t]) - 1)
+}
+
+func (d *Dict[V]) rehashing() bool {
+	return d.rehashIdx != -1
+}
+
+func (d *Dict[V]) expandIfNeeded() {
+	if d.rehashing() {
+		return
+	}
+	if len(d.tables[0]) == 0 {
+		d.tables[0] = make([]*dictEntry[V], dictInitialSize)
+		return
+	}
+	if d.used[0] >= len(d.tables[0]) {
+		d.resize(d.used[0] + 1)
+	}
+}
+
+func (d *Dict[V]) shrinkIfNeeded() {
+	if d.rehashing() || len(d.tables[0]) <= dictInitialSize {
+		return
+	}
+	if d.used[0]*100/len(d.tables[0]) < dictMinFill {
+		d.resize(max(d.used[0], dictInitialSize))
+	}
+}
+
+// resize starts rehashing into a table of at least size buckets.
+func (d *Dict[V]) resize(size int) {
+	n := dictInitialSize
+	for n < size {
+		n *= 2
+	}
+	if n == len(d.tables[0]) {
+		return
+	}
+	d.tables[1] = make([]*dictEntry[V], n)
+	d.used[1] = 0
+	d.rehashIdx = 0
+}
+
+// rehashStep moves one bucket of the old table to the new one, visiting at
+// most ten empty buckets on the way.
+func (d *Dict[V]) rehashStep() {
+	if !d.rehashing() {
+		return
+	}
+
+	for empty := 10; d.tables[0][d.rehashIdx] == nil; d.rehashIdx++ {
+		if d.rehashIdx == len(d.tables[0])-1 {
+			d.finishRehash()
+			return
+		}
+		if empty--; empty == 0 {
+			d.rehashIdx++
+			return
+		}
+	}
+
+	for e := d.tables[0][d.rehashIdx]; e != nil; {
+		next := e.next
+		idx := d.hash(e.key) & d.mask(1)
+		e.next = d.tables[1][idx]
+		d.tables[1][idx] = e
+		d.used[0]--
+		d.used[1]++
+		e = next
+	}
+	d.tables[0][d.rehashIdx] = nil
+	d.rehashIdx++
+
+	if d.used[0] == 0 {
+		d.finishRehash()
+	}
+}
+
+func (d *Dict[V]) finishRehash() {
+	d.tables[0], d.used[0] = d.tables[1], d.used[1]
+	d.tables[1], d.used[1] = nil, 0
+	d.rehashIdx = -1
+}
@@ -0,0 +1,313 @@
+package core
+
+import (
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+// Hash is the hash type. Small hashes are kept as a flat array of
+// field/value pairs (Redis's listpack encoding), which is compact and
+// cheap to search while short. A hash is converted to a Dict, for good, as
+// soon as it gets more fields than hash-max-listpack-entries or a field or
+// value longer than hash-max-listpack-value.
+//
+// Fields may have a TTL of their own (HEXPIRE & co.). An expired field is
+// treated as missing by every read, but stays in the hash, and in Len, until
+// DeleteExpired reclaims it.
+type Hash struct {
+	listpack []string // field, value, field, value, ... until converted
+	dict     *Dict[string]
+
+	expires   map[string]int64 // field -> unix ms, for fields with a TTL
+	minExpire int64            // earliest of expires, -1 if it must be recomputed
+}
+
+var (
+	hashMaxListpackEntries atomic.Int64
+	hashMaxListpackValue   atomic.Int64
+)
+
+func init() {
+	hashMaxListpackEntries.Store(128)
+	hashMaxListpackValue.Store(64)
+}
+
+// SetHashMaxListpackEntries changes hash-max-listpack-entries. Existing
+// hashes keep their encoding until they are next written to.
+func SetHashMaxListpackEntries(n int64) {
+	hashMaxListpackEntries.Store(n)
+}
+
+// SetHashMaxListpackValue changes hash-max-listpack-value.
+func SetHashMaxListpackValue(n int64) {
+	hashMaxListpackValue.Store(n)
+}
+
+func NewHash() *Hash {
+	return &Hash{}
+}
+
+func (h *Hash) Len() int {
+	if h.dict != nil {
+		return h.dict.Len()
+	}
+	return len(h.listpack) / 2
+}
+
+// Encoding returns "listpack", "listpackex" (a listpack with field TTLs)
+// or "hashtable", as OBJECT ENCODING would.
+func (h *Hash) Encoding() string {
+	switch {
+	case h.dict != nil:
+		return "hashtable"
+	case len(h.expires) > 0:
+		return "listpackex"
+	}
+	return "listpack"
+}
+
+func (h *Hash) Get(field string) (string, bool) {
+	if h.expired(field, time.Now().UnixMilli()) {
+		return "", false
+	}
+	return h.get(field)
+}
+
+func (h *Hash) get(field string) (string, bool) {
+	if h.dict != nil {
+		return h.dict.Get(field)
+	}
+	if i := h.find(field); i >= 0 {
+		return h.listpack[i+1], true
+	}
+	return "", false
+}
+
+// Set adds field or replaces its value, reporting whether it was added. A
+// replaced value loses its TTL.
+func (h *Hash) Set(field, value string) bool {
+	h.Persist(field)
+	return h.SetKeepTTL(field, value)
+}
+
+// SetKeepTTL is Set for updates of a value (HINCRBY) that keep its TTL.
+func (h *Hash) SetKeepTTL(field, value string) bool {
+	if h.dict == nil {
+		if i := h.find(field); i >= 0 {
+			h.listpack[i+1] = value
+			h.convertIfNeeded(field, value)
+			return false
+		}
+		h.listpack = append(h.listpack, field, value)
+		h.convertIfNeeded(field, value)
+		return true
+	}
+
+	_, exists := h.dict.Get(field)
+	h.dict.Set(field, value)
+	return !exists
+}
+
+// Delete removes field, reporting whether it was there.
+func (h *Hash) Delete(field string) bool {
+	h.Persist(field)
+	if h.dict != nil {
+		return h.dict.Delete(field)
+	}
+	i := h.find(field)
+	if i < 0 {
+		return false
+	}
+	h.listpack = append(h.listpack[:i], h.listpack[i+2:]...)
+	return true
+}
+
+// Range calls fn for every field until it returns false. fn must not
+// modify the hash.
+func (h *Hash) Range(fn func(field, value string) bool) {
+	now := time.Now().UnixMilli()
+	if h.dict != nil {
+		h.dict.Range(func(field, value string) bool {
+			return h.expired(field, now) || fn(field, value)
+		})
+		return
+	}
+	for i := 0; i < len(h.listpack); i += 2 {
+		if !h.expired(h.listpack[i], now) && !fn(h.listpack[i], h.listpack[i+1]) {
+			return
+		}
+	}
+}
+
+// Random returns a field picked at random and its value, false if the
+// hash is empty.
+func (h *Hash) Random() (string, string, bool) {
+	now := time.Now().UnixMilli()
+	for tries := 0; tries < 100; tries++ {
+		field, value, ok := h.random()
+		if !ok || !h.expired(field, now) {
+			return field, value, ok
+		}
+	}
+
+	// mostly expired: pick among the live fields
+	var fields, values []string
+	h.Range(func(field, value string) bool {
+		fields = append(fields, field)
+		values = append(values, value)
+		return true
+	})
+	if len(fields) == 0 {
+		return "", "", false
+	}
+	i := rand.IntN(len(fields))
+	return fields[i], values[i], true
+}
+
+func (h *Hash) random() (string, string, bool) {
+	if h.dict != nil {
+		field, ok := h.dict.RandomKey()
+		if !ok {
+			return "", "", false
+		}
+		value, _ := h.dict.Get(field)
+		return field, value, true
+	}
+	if len(h.listpack) == 0 {
+		return "", "", false
+	}
+	i := rand.IntN(len(h.listpack)/2) * 2
+	return h.listpack[i], h.listpack[i+1], true
+}
+
+// Scan is Dict.Scan for the hash. A listpack is small enough to be
+// returned whole by the first call, like Redis does.
+func (h *Hash) Scan(cursor uint64, fn func(field, value string)) uint64 {
+	if h.dict != nil {
+		now := time.Now().UnixMilli()
+		return h.dict.Scan(cursor, func(field, value string) {
+			if !h.expired(field, now) {
+				fn(field, value)
+			}
+		})
+	}
+	h.Range(func(field, value string) bool {
+		fn(field, value)
+		return true
+	})
+	return 0
+}
+
+// Dup returns a copy of the hash.
+func (h *Hash) Dup() *Hash {
+	dup := NewHash()
+	for field, when := range h.expires {
+		dup.SetExpire(field, when)
+	}
+	if h.dict == nil {
+		dup.listpack = append([]string(nil), h.listpack...)
+		return dup
+	}
+	dup.dict = NewDict[string]()
+	h.dict.Range(func(field, value string) bool {
+		dup.dict.Set(field, value)
+		return true
+	})
+	return dup
+}
+
+// ExpireAt returns the unix time in ms when field expires, 0 if it has no
+// TTL.
+func (h *Hash) ExpireAt(field string) int64 {
+	return h.expires[field]
+}
+
+// SetExpire sets the TTL of an existing field to the unix time when (in ms).
+func (h *Hash) SetExpire(field string, when int64) {
+	if h.expires == nil {
+		h.expires = make(map[string]int64)
+		h.minExpire = 0
+	}
+	h.expires[field] = when
+	if h.minExpire == 0 || (h.minExpire > 0 && when < h.minExpire) {
+		h.minExpire = when
+	}
+}
+
+// Persist removes the TTL of field, reporting whether it had one.
+func (h *Hash) Persist(field string) bool {
+	when, ok := h.expires[field]
+	if !ok {
+		return false
+	}
+	delete(h.expires, field)
+	if when == h.minExpire {
+		h.minExpire = -1
+	}
+	return true
+}
+
+// MinExpire returns when the first field with a TTL expires, 0 if no field
+// has one. It may have to recompute it, so it counts as a write.
+func (h *Hash) MinExpire() int64 {
+	if len(h.expires) == 0 {
+		return 0
+	}
+	if h.minExpire < 0 {
+		h.minExpire = 0
+		for _, when := range h.expires {
+			if h.minExpire == 0 || when < h.minExpire {
+				h.minExpire = when
+			}
+		}
+	}
+	return h.minExpire
+}
+
+// DeleteExpired removes the fields that expired by now and returns them.
+func (h *Hash) DeleteExpired(now int64) []string {
+	if min := h.MinExpire(); min == 0 || now <= min {
+		return nil
+	}
+	var fields []string
+	for field := range h.expires {
+		if h.expired(field, now) {
+			fields = append(fields, field)
+		}
+	}
+	for _, field := range fields {
+		h.Delete(field)
+	}
+	return fields
+}
+
+func (h *Hash) expired(field string, now int64) bool {
+	when, ok := h.expires[field]
+	return ok && now > when
+}
+
+// find returns the index of field in the listpack, -1 if it isn't there.
+func (h *Hash) find(field string) int {
+	for i := 0; i < len(h.listpack); i += 2 {
+		if h.listpack[i] == field {
+			return i
+		}
+	}
+	return -1
+}
+
+// convertIfNeeded switches to a Dict once the listpack, which now has
+// field set to value, outgrew the limits.
+func (h *Hash) convertIfNeeded(field, value string) {
+	maxValue := int(hashMaxListpackValue.Load())
+	if h.Len() <= int(hashMaxListpackEntries.Load()) && len(field) <= maxValue && len(value) <= maxValue {
+		return
+	}
+
+	h.dict = NewDict[string]()
+	for i := 0; i < len(h.listpack); i += 2 {
+		h.dict.Set(h.listpack[i], h.listpack[i+1])
+	}
+	h.listpack = nil
+}
@@ -0,0 +1,526 @@
+package core
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+	"sync/atomic"
+)
+
+// HyperLogLog is the value of a HyperLogLog key: a string laid out exactly
+// as Redis lays it out, so that it can be read with GET and moved between
+// servers byte for byte. It starts with a 16 byte header
+//
+//	"HYLL" | encoding | 3 unused bytes | cached cardinality (8 bytes, LE)
+//
+// followed by 16384 registers of 6 bits each. In the dense encoding they
+// are packed one after the other, least significant bits first. In the
+// sparse encoding they are run-length encoded with three opcodes:
+//
+//	ZERO  00xxxxxx           x+1 registers set to 0 (up to 64)
+//	XZERO 01xxxxxx yyyyyyyy  xy+1 registers set to 0 (up to 16384)
+//	VAL   1vvvvvxx           x+1 registers set to v+1 (up to 4, values to 32)
+//
+// A HyperLogLog starts sparse and is converted to dense, for good, once a
+// register needs a value the sparse encoding can't hold or it would grow
+// past hll-sparse-max-bytes. The most significant bit of the cached
+// cardinality is set when it must be recomputed.
+type HyperLogLog []byte
+
+const (
+	hllP          = 14 // registers are indexed by this many bits of the hash
+	hllQ          = 64 - hllP
+	hllBits       = 6
+	hllRegMax     = 1<<hllBits - 1
+	hllHeaderSize = 16
+	hllDenseSize  = hllHeaderSize + (HLLRegisters*hllBits+7)/8
+
+	hllDense  = 0
+	hllSparse = 1
+
+	hllZeroMaxLen  = 64
+	hllValMaxValue = 32
+	hllValMaxLen   = 4
+
+	hllAlphaInf = 0.721347520444481703680 // 0.5/ln(2)
+)
+
+// HLLRegisters is the number of registers of a HyperLogLog.
+const HLLRegisters = 1 << hllP
+
+var hllSparseMaxBytes atomic.Int64
+
+func init() {
+	hllSparseMaxBytes.Store(3000)
+}
+
+// SetHllSparseMaxBytes changes hll-sparse-max-bytes.
+func SetHllSparseMaxBytes(n int64) {
+	hllSparseMaxBytes.Store(n)
+}
+
+// NewHyperLogLog returns an empty, sparse HyperLogLog: a single XZERO
+// covering all the registers.
+func NewHyperLogLog() HyperLogLog {
+	h := make(HyperLogLog, hllHeaderSize, hllHeaderSize+2)
+	copy(h, "HYLL")
+	h[4] = hllSparse
+	return append(h, hllXzeroOp(HLLRegisters)...)
+}
+
+// ParseHyperLogLog returns a copy of s as a HyperLogLog, false if it
+// doesn't have a valid header. The registers aren't checked: operations
+// that find them corrupted report it.
+func ParseHyperLogLog(s string) (HyperLogLog, bool) {
+	if len(s) < hllHeaderSize || s[:4] != "HYLL" || s[4] > hllSparse {
+		return nil, false
+	}
+	if s[4] == hllDense && len(s) != hllDenseSize {
+		return nil, false
+	}
+	return HyperLogLog(s), true
+}
+
+func (h HyperLogLog) IsDense() bool {
+	return h[4] == hllDense
+}
+
+// CachedCount returns the cached cardinality, false if it's stale.
+func (h HyperLogLog) CachedCount() (uint64, bool) {
+	if h[15]&0x80 != 0 {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint64(h[8:16]), true
+}
+
+func (h HyperLogLog) SetCachedCount(card uint64) {
+	binary.LittleEndian.PutUint64(h[8:16], card)
+}
+
+func (h HyperLogLog) InvalidateCache() {
+	h[15] |= 0x80
+}
+
+// Add adds element, reporting whether a register changed, or false for ok
+// if the registers are corrupted.
+func (h *HyperLogLog) Add(element string) (updated, ok bool) {
+	index, count := hllPatLen(element)
+	if h.IsDense() {
+		return hllDenseSet((*h)[hllHeaderSize:], index, count), true
+	}
+	return h.sparseSet(index, count)
+}
+
+// Count estimates the cardinality, false if the registers are corrupted.
+func (h HyperLogLog) Count() (uint64, bool) {
+	var histogram [64]int
+	registers := h[hllHeaderSize:]
+	if h.IsDense() {
+		for i := 0; i < HLLRegisters; i++ {
+			histogram[hllDenseGet(registers, i)]++
+		}
+		return hllCount(&histogram), true
+	}
+
+	index := 0
+	for p := 0; p < len(registers); {
+		op := registers[p]
+		switch {
+		case hllIsZero(op):
+			histogram[0] += hllZeroLen(op)
+			index += hllZeroLen(op)
+			p++
+		case hllIsXzero(op):
+			if p+1 >= len(registers) {
+				return 0, false
+			}
+			n := hllXzeroLen(op, registers[p+1])
+			histogram[0] += n
+			index += n
+			p += 2
+		default:
+			histogram[hllValValue(op)] += hllValLen(op)
+			index += hllValLen(op)
+			p++
+		}
+	}
+	if index != HLLRegisters {
+		return 0, false
+	}
+	return hllCount(&histogram), true
+}
+
+// Merge raises each of merged, one byte per register, to the value of the
+// register in h. It reports false if the registers are corrupted.
+func (h HyperLogLog) Merge(merged []uint8) bool {
+	registers := h[hllHeaderSize:]
+	if h.IsDense() {
+		for i := 0; i < HLLRegisters; i++ {
+			merged[i] = max(merged[i], byte(hllDenseGet(registers, i)))
+		}
+		return true
+	}
+
+	index := 0
+	for p := 0; p < len(registers); p++ {
+		op := registers[p]
+		switch {
+		case hllIsZero(op):
+			index += hllZeroLen(op)
+		case hllIsXzero(op):
+			if p+1 >= len(registers) {
+				return false
+			}
+			index += hllXzeroLen(op, registers[p+1])
+			p++
+		default:
+			n, value := hllValLen(op), hllValValue(op)
+			if index+n > HLLRegisters {
+				return false
+			}
+			for ; n > 0; n-- {
+				merged[index] = max(merged[index], byte(value))
+				index++
+			}
+		}
+	}
+	return index == HLLRegisters
+}
+
+// SetRegisters raises the registers of h to the values in merged. With
+// dense set h is converted to the dense encoding first, as PFMERGE does
+// when any of its inputs is dense.
+func (h *HyperLogLog) SetRegisters(merged []uint8, dense bool) bool {
+	if dense {
+		if !h.toDense() {
+			return false
+		}
+		registers := (*h)[hllHeaderSize:]
+		for i, value := range merged {
+			hllDenseSet(registers, i, int(value))
+		}
+		return true
+	}
+	for i, value := range merged {
+		if value == 0 {
+			continue
+		}
+		if h.IsDense() {
+			hllDenseSet((*h)[hllHeaderSize:], i, int(value))
+		} else if _, ok := h.sparseSet(i, int(value)); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// CountRegisters estimates the cardinality of the HyperLogLog whose
+// registers are merged, one byte each, by Merge.
+func CountRegisters(merged []uint8) uint64 {
+	var histogram [64]int
+	for _, value := range merged {
+		histogram[value]++
+	}
+	return hllCount(&histogram)
+}
+
+// hllPatLen hashes element into the index of its register and the value
+// it proposes for it: the length of the run of zero bits that starts the
+// rest of the hash, plus one.
+func hllPatLen(element string) (int, int) {
+	hash := murmurHash64A(element, 0xadc83b19)
+	index := int(hash & (HLLRegisters - 1))
+	hash >>= hllP
+	hash |= 1 << hllQ // so that the count is at most Q+1
+	return index, bits.TrailingZeros64(hash) + 1
+}
+
+// murmurHash64A is MurmurHash2, 64-bit version, reading the input as
+// little endian.
+func murmurHash64A(key string, seed uint64) uint64 {
+	const m = 0xc6a4a7935bd1e995
+	const r = 47
+	h := seed ^ uint64(len(key))*m
+
+	for ; len(key) >= 8; key = key[8:] {
+		k := binary.LittleEndian.Uint64([]byte(key[:8]))
+		k *= m
+		k ^= k >> r
+		k *= m
+		h ^= k
+		h *= m
+	}
+	if len(key) > 0 {
+		for i := len(key) - 1; i >= 0; i-- {
+			h ^= uint64(key[i]) << (8 * i)
+		}
+		h *= m
+	}
+
+	h ^= h >> r
+	h *= m
+	h ^= h >> r
+	return h
+}
+
+// hllCount is the estimator of Otmar Ertl's "New cardinality estimation
+// algorithms for HyperLogLog sketches", given the histogram of register
+// values.
+func hllCount(histogram *[64]int) uint64 {
+	m := float64(HLLRegisters)
+	z := m * hllTau((m-float64(histogram[hllQ+1]))/m)
+	for j := hllQ; j >= 1; j-- {
+		z += float64(histogram[j])
+		z *= 0.5
+	}
+	z += m * hllSigma(float64(histogram[0])/m)
+	return uint64(math.Round(hllAlphaInf * m * m / z))
+}
+
+func hllSigma(x float64) float64 {
+	if x == 1 {
+		return math.Inf(1)
+	}
+	y, z := 1.0, x
+	for {
+		x *= x
+		prev := z
+		z += x * y
+		y += y
+		if z == prev {
+			return z
+		}
+	}
+}
+
+func hllTau(x float64) float64 {
+	if x == 0 || x == 1 {
+		return 0
+	}
+	y, z := 1.0, 1-x
+	for {
+		x = math.Sqrt(x)
+		prev := z
+		y *= 0.5
+		z -= math.Pow(1-x, 2) * y
+		if z == prev {
+			return z / 3
+		}
+	}
+}
+
+// Dense registers. The last one ends in the last byte, so the byte after
+// it is only touched when there is one.
+
+func hllDenseGet(registers []byte, index int) int {
+	i := index * hllBits / 8
+	shift := uint(index * hllBits & 7)
+	v := int(registers[i] >> shift)
+	if i+1 < len(registers) {
+		v |= int(registers[i+1]) << (8 - shift)
+	}
+	return v & hllRegMax
+}
+
+// hllDenseSet raises the register at index to count, reporting whether it
+// was lower.
+func hllDenseSet(registers []byte, index, count int) bool {
+	if count <= hllDenseGet(registers, index) {
+		return false
+	}
+	i := index * hllBits / 8
+	shift := uint(index * hllBits & 7)
+	registers[i] &^= hllRegMax << shift
+	registers[i] |= byte(count << shift)
+	if i+1 < len(registers) {
+		registers[i+1] &^= hllRegMax >> (8 - shift)
+		registers[i+1] |= byte(count >> (8 - shift))
+	}
+	return true
+}
+
+// Sparse opcodes.
+
+func hllIsZero(op byte) bool  { return op&0xc0 == 0 }
+func hllIsXzero(op byte) bool { return op&0xc0 == 0x40 }
+func hllIsVal(op byte) bool   { return op&0x80 != 0 }
+
+func hllZeroLen(op byte) int        { return int(op&0x3f) + 1 }
+func hllXzeroLen(op, next byte) int { return (int(op&0x3f)<<8 | int(next)) + 1 }
+func hllValValue(op byte) int       { return int(op>>2&0x1f) + 1 }
+func hllValLen(op byte) int         { return int(op&0x3) + 1 }
+
+func hllZeroOp(n int) byte       { return byte(n - 1) }
+func hllXzeroOp(n int) []byte    { return []byte{byte((n-1)>>8) | 0x40, byte((n - 1) & 0xff)} }
+func hllValOp(value, n int) byte { return byte((value-1)<<2|(n-1)) | 0x80 }
+func hllZerosOp(n int) []byte {
+	if n > hllZeroMaxLen {
+		return hllXzeroOp(n)
+	}
+	return []byte{hllZeroOp(n)}
+}
+
+// sparseSet raises the register at index to count, like hllDenseSet, by
+// splitting the opcode covering it. The result is byte for byte what
+// Redis's hllSparseSet produces, as the encoding of a HyperLogLog depends
+// on the order its registers were set in.
+func (h *HyperLogLog) sparseSet(index, count int) (updated, ok bool) {
+	if count > hllValMaxValue {
+		return h.promote(index, count)
+	}
+
+	// find the opcode covering index
+	b := *h
+	end := len(b)
+	p, prev := hllHeaderSize, -1
+	first, span := 0, 0
+	for p < end {
+		oplen := 1
+		switch op := b[p]; {
+		case hllIsZero(op):
+			span = hllZeroLen(op)
+		case hllIsVal(op):
+			span = hllValLen(op)
+		default:
+			if p+1 >= end {
+				return false, false
+			}
+			span = hllXzeroLen(op, b[p+1])
+			oplen = 2
+		}
+		if index <= first+span-1 {
+			break
+		}
+		prev = p
+		p += oplen
+		first += span
+	}
+	if span == 0 || p >= end {
+		return false, false
+	}
+
+	op := b[p]
+	switch {
+	case hllIsVal(op) && hllValValue(op) >= count:
+		return false, true
+	case hllIsVal(op) && hllValLen(op) == 1, hllIsZero(op) && hllZeroLen(op) == 1:
+		b[p] = hllValOp(count, 1)
+	default:
+		// replace the opcode with up to three: the registers before index,
+		// index itself, and the ones after it
+		last := first + span - 1
+		var seq []byte
+		oldLen := 1
+		if hllIsVal(op) {
+			value := hllValValue(op)
+			if index != first {
+				seq = append(seq, hllValOp(value, index-first))
+			}
+			seq = append(seq, hllValOp(count, 1))
+			if index != last {
+				seq = append(seq, hllValOp(value, last-index))
+			}
+		} else {
+			if hllIsXzero(op) {
+				oldLen = 2
+			}
+			if index != first {
+				seq = append(seq, hllZerosOp(index-first)...)
+			}
+			seq = append(seq, hllValOp(count, 1))
+			if index != last {
+				seq = append(seq, hllZerosOp(last-index)...)
+			}
+		}
+
+		delta := len(seq) - oldLen
+		if delta > 0 && int64(len(b)+delta) > hllSparseMaxBytes.Load() {
+			return h.promote(index, count)
+		}
+		b = append(b[:p], append(seq, b[p+oldLen:]...)...)
+		end = len(b)
+	}
+
+	// merge adjacent VALs of the same value, scanning up to 5 opcodes from
+	// the one before the change
+	p = prev
+	if p < 0 {
+		p = hllHeaderSize
+	}
+	for scan := 5; p < end && scan > 0; scan-- {
+		op := b[p]
+		if hllIsXzero(op) {
+			p += 2
+			continue
+		}
+		if hllIsZero(op) {
+			p++
+			continue
+		}
+		if p+1 < end && hllIsVal(b[p+1]) && hllValValue(op) == hllValValue(b[p+1]) {
+			if n := hllValLen(op) + hllValLen(b[p+1]); n <= hllValMaxLen {
+				b[p+1] = hllValOp(hllValValue(op), n)
+				b = append(b[:p], b[p+1:]...)
+				end--
+				continue
+			}
+		}
+		p++
+	}
+
+	*h = b
+	h.InvalidateCache()
+	return true, true
+}
+
+// promote converts h to the dense encoding and sets the register there;
+// it always changes it, or the sparse encoding would have sufficed.
+func (h *HyperLogLog) promote(index, count int) (updated, ok bool) {
+	if !h.toDense() {
+		return false, false
+	}
+	hllDenseSet((*h)[hllHeaderSize:], index, count)
+	return true, true
+}
+
+// toDense converts h to the dense encoding, keeping its header, false if
+// its registers are corrupted.
+func (h *HyperLogLog) toDense() bool {
+	if h.IsDense() {
+		return true
+	}
+	sparse := *h
+	dense := make(HyperLogLog, hllDenseSize)
+	copy(dense, sparse[:hllHeaderSize])
+	dense[4] = hllDense
+	registers := dense[hllHeaderSize:]
+
+	index := 0
+	for p := hllHeaderSize; p < len(sparse); p++ {
+		op := sparse[p]
+		switch {
+		case hllIsZero(op):
+			index += hllZeroLen(op)
+		case hllIsXzero(op):
+			if p+1 >= len(sparse) {
+				return false
+			}
+			index += hllXzeroLen(op, sparse[p+1])
+			p++
+		default:
+			n, value := hllValLen(op), hllValValue(op)
+			if index+n > HLLRegisters {
+				return false
+			}
+			for ; n > 0; n-- {
+				hllDenseSet(registers, index, value)
+				index++
+			}
+		}
+	}
+	if index != HLLRegisters {
+		return false
+	}
+	*h = dense
+	return true
+}
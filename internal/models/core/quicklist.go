@@ -0,0 +1,347 @@
+package core
+
+// Limits of a single quicklist node, matching Redis's default
+// list-max-listpack-size of -2 (8kb per node).
+const (
+	quicklistNodeMaxEntries = 128
+	quicklistNodeMaxBytes   = 8 * 1024
+)
+
+// Quicklist is the list type: a doubly linked list of nodes that each hold
+// a small array of elements. Pushing and popping at either end is O(1),
+// and the arrays keep memory overhead and pointer chasing low compared to
+// one node per element. Indexes may be negative to count from the tail.
+type Quicklist struct {
+	head, tail *quicklistNode
+	count      int // elements
+}
+
+type quicklistNode struct {
+	prev, next *quicklistNode
+	entries    []string
+	size       int // bytes in entries
+}
+
+func NewQuicklist() *Quicklist {
+	return &Quicklist{}
+}
+
+func (q *Quicklist) Len() int {
+	return q.count
+}
+
+func (q *Quicklist) PushHead(value string) {
+	if q.head == nil || !q.head.fits(value) {
+		q.linkBefore(q.head, &quicklistNode{})
+	}
+	n := q.head
+	n.entries = append(n.entries, "")
+	copy(n.entries[1:], n.entries)
+	n.entries[0] = value
+	n.size += len(value)
+	q.count++
+}
+
+func (q *Quicklist) PushTail(value string) {
+	if q.tail == nil || !q.tail.fits(value) {
+		q.linkAfter(q.tail, &quicklistNode{})
+	}
+	n := q.tail
+	n.entries = append(n.entries, value)
+	n.size += len(value)
+	q.count++
+}
+
+func (q *Quicklist) PopHead() (string, bool) {
+	if q.count == 0 {
+		return "", false
+	}
+	value := q.head.entries[0]
+	q.deleteAt(q.head, 0)
+	return value, true
+}
+
+func (q *Quicklist) PopTail() (string, bool) {
+	if q.count == 0 {
+		return "", false
+	}
+	value := q.tail.entries[len(q.tail.entries)-1]
+	q.deleteAt(q.tail, len(q.tail.entries)-1)
+	return value, true
+}
+
+func (q *Quicklist) Index(i int) (string, bool) {
+	n, off, ok := q.locate(i)
+	if !ok {
+		return "", false
+	}
+	return n.entries[off], true
+}
+
+// Set replaces the element at index i, reporting false if it's out of
+// range.
+func (q *Quicklist) Set(i int, value string) bool {
+	n, off, ok := q.locate(i)
+	if !ok {
+		return false
+	}
+	n.size += len(value) - len(n.entries[off])
+	n.entries[off] = value
+	return true
+}
+
+// Insert adds value so that it ends up at index i, 0 <= i <= Len().
+func (q *Quicklist) Insert(i int, value string) {
+	switch {
+	case i <= 0:
+		q.PushHead(value)
+		return
+	case i >= q.count:
+		q.PushTail(value)
+		return
+	}
+
+	n, off, _ := q.locate(i)
+	n.entries = append(n.entries, "")
+	copy(n.entries[off+1:], n.entries[off:])
+	n.entries[off] = value
+	n.size += len(value)
+	q.count++
+
+	if len(n.entries) > quicklistNodeMaxEntries || n.size > quicklistNodeMaxBytes {
+		q.split(n)
+	}
+}
+
+// Range calls fn for the elements from index start to stop inclusive, both
+// already within range, until fn returns false.
+func (q *Quicklist) Range(start, stop int, fn func(value string) bool) {
+	n, off, ok := q.locate(start)
+	for i := start; ok && i <= stop; i++ {
+		if !fn(n.entries[off]) {
+			return
+		}
+		if off++; off == len(n.entries) {
+			n, off = n.next, 0
+			ok = n != nil
+		}
+	}
+}
+
+// Iterate calls fn with every element and its index, from the tail when
+// reverse is set, until fn returns false.
+func (q *Quicklist) Iterate(reverse bool, fn func(i int, value string) bool) {
+	if !reverse {
+		i := 0
+		for n := q.head; n != nil; n = n.next {
+			for _, value := range n.entries {
+				if !fn(i, value) {
+					return
+				}
+				i++
+			}
+		}
+		return
+	}
+
+	i := q.count - 1
+	for n := q.tail; n != nil; n = n.prev {
+		for off := len(n.entries) - 1; off >= 0; off-- {
+			if !fn(i, n.entries[off]) {
+				return
+			}
+			i--
+		}
+	}
+}
+
+// DeleteRange deletes count elements starting at index start, both already
+// within range.
+func (q *Quicklist) DeleteRange(start, count int) {
+	n, off, ok := q.locate(start)
+	for ok && count > 0 {
+		k := min(count, len(n.entries)-off)
+		next := n.next
+		if k == len(n.entries) {
+			q.unlink(n)
+		} else {
+			for _, value := range n.entries[off : off+k] {
+				n.size -= len(value)
+			}
+			n.entries = append(n.entries[:off], n.entries[off+k:]...)
+		}
+		q.count -= k
+		count -= k
+		n, off, ok = next, 0, next != nil
+	}
+}
+
+// RemoveIf deletes up to limit elements (all of them if limit is 0) for
+// which match returns true, scanning from the tail when reverse is set. It
+// returns how many were deleted.
+func (q *Quicklist) RemoveIf(reverse bool, limit int, match func(value string) bool) int {
+	removed := 0
+	n := q.head
+	if reverse {
+		n = q.tail
+	}
+	for n != nil && (limit == 0 || removed < limit) {
+		next := n.next
+		if reverse {
+			next = n.prev
+		}
+
+		off := 0
+		if reverse {
+			off = len(n.entries) - 1
+		}
+		for off >= 0 && off < len(n.entries) && (limit == 0 || removed < limit) {
+			if !match(n.entries[off]) {
+				if reverse {
+					off--
+				} else {
+					off++
+				}
+				continue
+			}
+			// deleting at off shifts the rest down, so off now points at
+			// the next element forwards; backwards it's off-1
+			last := len(n.entries) == 1
+			q.deleteAt(n, off)
+			removed++
+			if last {
+				break
+			}
+			if reverse {
+				off--
+			}
+		}
+		n = next
+	}
+	return removed
+}
+
+// Dup returns a copy of the list.
+func (q *Quicklist) Dup() *Quicklist {
+	dup := NewQuicklist()
+	for n := q.head; n != nil; n = n.next {
+		entries := make([]string, len(n.entries))
+		copy(entries, n.entries)
+		dup.linkAfter(dup.tail, &quicklistNode{entries: entries, size: n.size})
+	}
+	dup.count = q.count
+	return dup
+}
+
+// locate finds the node and offset of index i, which may be negative.
+func (q *Quicklist) locate(i int) (*quicklistNode, int, bool) {
+	if i < 0 {
+		i += q.count
+	}
+	if i < 0 || i >= q.count {
+		return nil, 0, false
+	}
+
+	if i < q.count/2 {
+		for n := q.head; n != nil; n = n.next {
+			if i < len(n.entries) {
+				return n, i, true
+			}
+			i -= len(n.entries)
+		}
+	}
+	fromTail := q.count - 1 - i
+	for n := q.tail; n != nil; n = n.prev {
+		if fromTail < len(n.entries) {
+			return n, len(n.entries) - 1 - fromTail, true
+		}
+		fromTail -= len(n.entries)
+	}
+	return nil, 0, false
+}
+
+func (q *Quicklist) deleteAt(n *quicklistNode, off int) {
+	q.count--
+	if len(n.entries) == 1 {
+		q.unlink(n)
+		return
+	}
+	n.size -= len(n.entries[off])
+	copy(n.entries[off:], n.entries[off+1:])
+	n.entries[len(n.entries)-1] = ""
+	n.entries = n.entries[:len(n.entries)-1]
+}
+
+// split moves the second half of n's entries into a new node after it.
+func (q *Quicklist) split(n *quicklistNode) {
+	half := len(n.entries) / 2
+	moved := make([]string, len(n.entries)-half)
+	copy(moved, n.entries[half:])
+	n.entries = n.entries[:half:half]
+
+	second := &quicklistNode{entries: moved}
+	for _, value := range moved {
+		second.size += len(value)
+	}
+	n.size -= second.size
+	q.linkAfter(n, second)
+}
+
+// linkBefore inserts node before at (at the tail if at is nil).
+func (q *Quicklist) linkBefore(at, node *quicklistNode) {
+	if at == nil {
+		q.linkAfter(q.tail, node)
+		return
+	}
+	node.next, node.prev = at, at.prev
+	if at.prev != nil {
+		at.prev.next = node
+	} else {
+		q.head = node
+	}
+	at.prev = node
+}
+
+// linkAfter inserts node after at (at the head if at is nil).
+func (q *Quicklist) linkAfter(at, node *quicklistNode) {
+	if at == nil {
+		node.prev, node.next = nil, q.head
+		if q.head != nil {
+			q.head.prev = node
+		} else {
+			q.tail = node
+		}
+		q.head = node
+		return
+	}
+	node.prev, node.next = at, at.next
+	if at.next != nil {
+		at.next.prev = node
+	} else {
+		q.tail = node
+	}
+	at.next = node
+}
+
+func (q *Quicklist) unlink(n *quicklistNode) {
+	if n.prev != nil {
+		n.prev.next = n.next
+	} else {
+		q.head = n.next
+	}
+	if n.next != nil {
+		n.next.prev = n.prev
+	} else {
+		q.tail = n.prev
+	}
+	n.prev, n.next = nil, nil
+}
+
+// fits reports whether value can be added to n without exceeding the node
+// limits. An empty node takes any value, however big.
+func (n *quicklistNode) fits(value string) bool {
+	if len(n.entries) == 0 {
+		return true
+	}
+	return len(n.entries) < quicklistNodeMaxEntries && n.size+len(value) <= quicklistNodeMaxBytes
+}
@@ -0,0 +1,187 @@
+package core
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"sync/atomic"
+)
+
+// Set is the set type. A set made only of integers is kept as a sorted
+// array of them (Redis's intset encoding), which is far smaller than a
+// hash table and still searched in O(log n). It's converted to a Dict,
+// for good, once it gets a member that isn't an integer or more than
+// set-max-intset-entries members.
+type Set struct {
+	intset []int64 // sorted, until converted
+	dict   *Dict[struct{}]
+}
+
+var setMaxIntsetEntries atomic.Int64
+
+func init() {
+	setMaxIntsetEntries.Store(512)
+}
+
+// SetSetMaxIntsetEntries changes set-max-intset-entries.
+func SetSetMaxIntsetEntries(n int64) {
+	setMaxIntsetEntries.Store(n)
+}
+
+func NewSet() *Set {
+	return &Set{}
+}
+
+func (s *Set) Len() int {
+	if s.dict != nil {
+		return s.dict.Len()
+	}
+	return len(s.intset)
+}
+
+// Encoding returns "intset" or "hashtable", as OBJECT ENCODING would.
+func (s *Set) Encoding() string {
+	if s.dict != nil {
+		return "hashtable"
+	}
+	return "intset"
+}
+
+func (s *Set) Contains(member string) bool {
+	if s.dict != nil {
+		_, ok := s.dict.Get(member)
+		return ok
+	}
+	n, ok := intsetValue(member)
+	if !ok {
+		return false
+	}
+	_, found := slices.BinarySearch(s.intset, n)
+	return found
+}
+
+// Add adds member, reporting whether it wasn't there yet.
+func (s *Set) Add(member string) bool {
+	if s.dict == nil {
+		n, ok := intsetValue(member)
+		if ok {
+			i, found := slices.BinarySearch(s.intset, n)
+			if found {
+				return false
+			}
+			if int64(len(s.intset)) < setMaxIntsetEntries.Load() {
+				s.intset = slices.Insert(s.intset, i, n)
+				return true
+			}
+		}
+		s.convert()
+	}
+
+	if _, exists := s.dict.Get(member); exists {
+		return false
+	}
+	s.dict.Set(member, struct{}{})
+	return true
+}
+
+// Remove removes member, reporting whether it was there.
+func (s *Set) Remove(member string) bool {
+	if s.dict != nil {
+		return s.dict.Delete(member)
+	}
+	n, ok := intsetValue(member)
+	if !ok {
+		return false
+	}
+	i, found := slices.BinarySearch(s.intset, n)
+	if found {
+		s.intset = slices.Delete(s.intset, i, i+1)
+	}
+	return found
+}
+
+// Range calls fn for every member until it returns false. fn must not
+// modify the set.
+func (s *Set) Range(fn func(member string) bool) {
+	if s.dict != nil {
+		s.dict.Range(func(member string, _ struct{}) bool {
+			return fn(member)
+		})
+		return
+	}
+	for _, n := range s.intset {
+		if !fn(strconv.FormatInt(n, 10)) {
+			return
+		}
+	}
+}
+
+// Members returns all the members.
+func (s *Set) Members() []string {
+	members := make([]string, 0, s.Len())
+	s.Range(func(member string) bool {
+		members = append(members, member)
+		return true
+	})
+	return members
+}
+
+// Random returns a member picked at random, false if the set is empty.
+func (s *Set) Random() (string, bool) {
+	if s.dict != nil {
+		return s.dict.RandomKey()
+	}
+	if len(s.intset) == 0 {
+		return "", false
+	}
+	return strconv.FormatInt(s.intset[rand.IntN(len(s.intset))], 10), true
+}
+
+// Scan is Dict.Scan for the set. An intset is returned whole by the first
+// call, like Redis does.
+func (s *Set) Scan(cursor uint64, fn func(member string)) uint64 {
+	if s.dict != nil {
+		return s.dict.Scan(cursor, func(member string, _ struct{}) {
+			fn(member)
+		})
+	}
+	s.Range(func(member string) bool {
+		fn(member)
+		return true
+	})
+	return 0
+}
+
+// Dup returns a copy of the set.
+func (s *Set) Dup() *Set {
+	dup := NewSet()
+	if s.dict == nil {
+		dup.intset = slices.Clone(s.intset)
+		return dup
+	}
+	dup.dict = NewDict[struct{}]()
+	s.dict.Range(func(member string, _ struct{}) bool {
+		dup.dict.Set(member, struct{}{})
+		return true
+	})
+	return dup
+}
+
+func (s *Set) convert() {
+	s.dict = NewDict[struct{}]()
+	for _, n := range s.intset {
+		s.dict.Set(strconv.FormatInt(n, 10), struct{}{})
+	}
+	s.intset = nil
+}
+
+// intsetValue parses member as an intset entry. Only the canonical form of
+// an integer qualifies ("1", not "01" or "+1"), so that members read back
+// from the intset are exactly what was added.
+func intsetValue(member string) (int64, bool) {
+	n, err := strconv.ParseInt(member, 10, 64)
+	if err != nil || strconv.FormatInt(n, 10) != member {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,514 @@
+package core
+
+import (
+	"math/rand/v2"
+)
+
+// Skiplist parameters, as in Redis: a node gets each further level with
+// probability 1/4, so the expected number of levels is 1.33.
+const (
+	zskiplistMaxLevel = 32
+	zskiplistP        = 0.25
+)
+
+// SortedSet is the sorted set type: a Dict from member to score, for O(1)
+// ZSCORE and member lookups, and a skiplist ordered by (score, member).
+// The skiplist keeps the span of every link, so the rank of a member and
+// the member at a rank are found in O(log n) like a balanced tree would,
+// and ranges are walked in order.
+type SortedSet struct {
+	dict *Dict[float64]
+	zsl  *zskiplist
+}
+
+// ScoreRange is a range of scores, each end inclusive unless marked
+// exclusive. Min and Max may be infinite.
+type ScoreRange struct {
+	Min, Max     float64
+	MinEx, MaxEx bool
+}
+
+// LexBound is one end of a range of members compared as byte strings: "-"
+// (Inf < 0) and "+" (Inf > 0) stand for the smallest and largest string.
+type LexBound struct {
+	Value     string
+	Exclusive bool
+	Inf       int
+}
+
+// LexRange is a range of members; it's only meaningful when all the
+// members have the same score.
+type LexRange struct {
+	Min, Max LexBound
+}
+
+type zskiplist struct {
+	header, tail *zskiplistNode
+	length       int
+	level        int
+}
+
+type zskiplistNode struct {
+	member   string
+	score    float64
+	backward *zskiplistNode
+	level    []zskiplistLevel
+}
+
+type zskiplistLevel struct {
+	forward *zskiplistNode
+	span    int // nodes this link skips over, counting the one it points to
+}
+
+func NewSortedSet() *SortedSet {
+	return &SortedSet{dict: NewDict[float64](), zsl: newSkiplist()}
+}
+
+func (z *SortedSet) Len() int {
+	return z.zsl.length
+}
+
+func (z *SortedSet) Score(member string) (float64, bool) {
+	return z.dict.Get(member)
+}
+
+// Add adds member with score, or moves it to score if it's already there.
+// It reports whether the member was added.
+func (z *SortedSet) Add(member string, score float64) bool {
+	current, exists := z.dict.Get(member)
+	if !exists {
+		z.dict.Set(member, score)
+		z.zsl.insert(score, member)
+		return true
+	}
+	if current != score {
+		z.zsl.updateScore(current, member, score)
+		z.dict.Set(member, score)
+	}
+	return false
+}
+
+// Remove removes member, reporting whether it was there.
+func (z *SortedSet) Remove(member string) bool {
+	score, exists := z.dict.Get(member)
+	if !exists {
+		return false
+	}
+	z.dict.Delete(member)
+	z.zsl.delete(score, member)
+	return true
+}
+
+// Rank returns the 0-based rank of member, counted from the highest score
+// when reverse is set.
+func (z *SortedSet) Rank(member string, reverse bool) (int, bool) {
+	score, exists := z.dict.Get(member)
+	if !exists {
+		return 0, false
+	}
+	rank := z.zsl.rank(score, member)
+	if reverse {
+		return z.zsl.length - rank, true
+	}
+	return rank - 1, true
+}
+
+// RangeByRank calls fn for the members from rank start to stop inclusive,
+// both already within range, in order or, when reverse is set, from the
+// highest score down. It stops early if fn returns false.
+func (z *SortedSet) RangeByRank(start, stop int, reverse bool, fn func(member string, score float64) bool) {
+	var node *zskiplistNode
+	if reverse {
+		node = z.zsl.byRank(z.zsl.length - start)
+	} else {
+		node = z.zsl.byRank(start + 1)
+	}
+	for n := stop - start + 1; node != nil && n > 0; n-- {
+		if !fn(node.member, node.score) {
+			return
+		}
+		node = z.zsl.next(node, reverse)
+	}
+}
+
+// RangeByScore calls fn for the members with a score in r, in order or
+// from the highest score down, skipping the first offset of them and
+// stopping after limit (if limit >= 0) or when fn returns false.
+func (z *SortedSet) RangeByScore(r ScoreRange, reverse bool, offset, limit int, fn func(member string, score float64) bool) {
+	var node *zskiplistNode
+	if reverse {
+		node = z.zsl.lastInScoreRange(r)
+	} else {
+		node = z.zsl.firstInScoreRange(r)
+	}
+	node = z.zsl.skip(node, offset, reverse)
+	for ; node != nil && limit != 0; limit-- {
+		if reverse && !r.aboveMin(node.score) || !reverse && !r.belowMax(node.score) {
+			return
+		}
+		if !fn(node.member, node.score) {
+			return
+		}
+		node = z.zsl.next(node, reverse)
+	}
+}
+
+// RangeByLex is RangeByScore for a range of members.
+func (z *SortedSet) RangeByLex(r LexRange, reverse bool, offset, limit int, fn func(member string, score float64) bool) {
+	var node *zskiplistNode
+	if reverse {
+		node = z.zsl.lastInLexRange(r)
+	} else {
+		node = z.zsl.firstInLexRange(r)
+	}
+	node = z.zsl.skip(node, offset, reverse)
+	for ; node != nil && limit != 0; limit-- {
+		if reverse && !r.Min.below(node.member) || !reverse && !r.Max.above(node.member) {
+			return
+		}
+		if !fn(node.member, node.score) {
+			return
+		}
+		node = z.zsl.next(node, reverse)
+	}
+}
+
+// CountByScore returns the number of members with a score in r.
+func (z *SortedSet) CountByScore(r ScoreRange) int {
+	first := z.zsl.firstInScoreRange(r)
+	if first == nil {
+		return 0
+	}
+	last := z.zsl.lastInScoreRange(r)
+	return z.zsl.rank(last.score, last.member) - z.zsl.rank(first.score, first.member) + 1
+}
+
+// CountByLex returns the number of members in r.
+func (z *SortedSet) CountByLex(r LexRange) int {
+	first := z.zsl.firstInLexRange(r)
+	if first == nil {
+		return 0
+	}
+	last := z.zsl.lastInLexRange(r)
+	return z.zsl.rank(last.score, last.member) - z.zsl.rank(first.score, first.member) + 1
+}
+
+// Scan is Dict.Scan over the members and their scores.
+func (z *SortedSet) Scan(cursor uint64, fn func(member string, score float64)) uint64 {
+	return z.dict.Scan(cursor, fn)
+}
+
+// Range calls fn for every member in order until it returns false.
+func (z *SortedSet) Range(fn func(member string, score float64) bool) {
+	for node := z.zsl.header.level[0].forward; node != nil; node = node.level[0].forward {
+		if !fn(node.member, node.score) {
+			return
+		}
+	}
+}
+
+// Dup returns a copy of the sorted set.
+func (z *SortedSet) Dup() *SortedSet {
+	dup := NewSortedSet()
+	z.Range(func(member string, score float64) bool {
+		dup.Add(member, score)
+		return true
+	})
+	return dup
+}
+
+func (r ScoreRange) aboveMin(score float64) bool {
+	if r.MinEx {
+		return score > r.Min
+	}
+	return score >= r.Min
+}
+
+func (r ScoreRange) belowMax(score float64) bool {
+	if r.MaxEx {
+		return score < r.Max
+	}
+	return score <= r.Max
+}
+
+// Empty reports whether no score can be in r.
+func (r ScoreRange) Empty() bool {
+	return r.Min > r.Max || (r.Min == r.Max && (r.MinEx || r.MaxEx))
+}
+
+// below reports whether the bound, as a minimum, lets member in.
+func (b LexBound) below(member string) bool {
+	switch {
+	case b.Inf != 0:
+		return b.Inf < 0
+	case b.Exclusive:
+		return member > b.Value
+	}
+	return member >= b.Value
+}
+
+// above reports whether the bound, as a maximum, lets member in.
+func (b LexBound) above(member string) bool {
+	switch {
+	case b.Inf != 0:
+		return b.Inf > 0
+	case b.Exclusive:
+		return member < b.Value
+	}
+	return member <= b.Value
+}
+
+// Empty reports whether no member can be in r.
+func (r LexRange) Empty() bool {
+	switch {
+	case r.Min.Inf > 0 || r.Max.Inf < 0:
+		return true
+	case r.Min.Inf < 0 || r.Max.Inf > 0:
+		return false
+	}
+	return r.Min.Value > r.Max.Value || (r.Min.Value == r.Max.Value && (r.Min.Exclusive || r.Max.Exclusive))
+}
+
+func newSkiplist() *zskiplist {
+	return &zskiplist{
+		header: &zskiplistNode{level: make([]zskiplistLevel, zskiplistMaxLevel)},
+		level:  1,
+	}
+}
+
+func randomLevel() int {
+	level := 1
+	for level < zskiplistMaxLevel && rand.Float64() < zskiplistP {
+		level++
+	}
+	return level
+}
+
+// less orders nodes by score, then member.
+func (n *zskiplistNode) less(score float64, member string) bool {
+	return n.score < score || (n.score == score && n.member < member)
+}
+
+func (zsl *zskiplist) insert(score float64, member string) {
+	var update [zskiplistMaxLevel]*zskiplistNode
+	var rank [zskiplistMaxLevel]int
+
+	x := zsl.header
+	for i := zsl.level - 1; i >= 0; i-- {
+		if i < zsl.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.level[i].forward != nil && x.level[i].forward.less(score, member) {
+			rank[i] += x.level[i].span
+			x = x.level[i].forward
+		}
+		update[i] = x
+	}
+
+	level := randomLevel()
+	if level > zsl.level {
+		for i := zsl.level; i < level; i++ {
+			rank[i] = 0
+			update[i] = zsl.header
+			update[i].level[i].span = zsl.length
+		}
+		zsl.level = level
+	}
+
+	x = &zskiplistNode{member: member, score: score, level: make([]zskiplistLevel, level)}
+	for i := 0; i < level; i++ {
+		x.level[i].forward = update[i].level[i].forward
+		update[i].level[i].forward = x
+		x.level[i].span = update[i].level[i].span - (rank[0] - rank[i])
+		update[i].level[i].span = rank[0] - rank[i] + 1
+	}
+	// levels above the new node's now skip one more node
+	for i := level; i < zsl.level; i++ {
+		update[i].level[i].span++
+	}
+
+	if update[0] != zsl.header {
+		x.backward = update[0]
+	}
+	if x.level[0].forward != nil {
+		x.level[0].forward.backward = x
+	} else {
+		zsl.tail = x
+	}
+	zsl.length++
+}
+
+func (zsl *zskiplist) delete(score float64, member string) {
+	var update [zskiplistMaxLevel]*zskiplistNode
+
+	x := zsl.header
+	for i := zsl.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil && x.level[i].forward.less(score, member) {
+			x = x.level[i].forward
+		}
+		update[i] = x
+	}
+
+	x = x.level[0].forward
+	if x != nil && x.score == score && x.member == member {
+		zsl.unlink(x, update[:zsl.level])
+	}
+}
+
+func (zsl *zskiplist) unlink(x *zskiplistNode, update []*zskiplistNode) {
+	for i := range update {
+		if update[i].level[i].forward == x {
+			update[i].level[i].span += x.level[i].span - 1
+			update[i].level[i].forward = x.level[i].forward
+		} else {
+			update[i].level[i].span--
+		}
+	}
+	if x.level[0].forward != nil {
+		x.level[0].forward.backward = x.backward
+	} else {
+		zsl.tail = x.backward
+	}
+	for zsl.level > 1 && zsl.header.level[zsl.level-1].forward == nil {
+		zsl.level--
+	}
+	zsl.length--
+}
+
+// updateScore moves member from score to newScore. If it would stay where
+// it is in the order, only the score changes.
+func (zsl *zskiplist) updateScore(score float64, member string, newScore float64) {
+	x := zsl.header
+	for i := zsl.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil && x.level[i].forward.less(score, member) {
+			x = x.level[i].forward
+		}
+	}
+	x = x.level[0].forward
+
+	prev, next := x.backward, x.level[0].forward
+	if (prev == nil || prev.less(newScore, member)) && (next == nil || !next.less(newScore, member)) {
+		x.score = newScore
+		return
+	}
+	zsl.delete(score, member)
+	zsl.insert(newScore, member)
+}
+
+// rank returns the 1-based rank of a node that's in the list.
+func (zsl *zskiplist) rank(score float64, member string) int {
+	rank := 0
+	x := zsl.header
+	for i := zsl.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil && !(score < x.level[i].forward.score ||
+			score == x.level[i].forward.score && member < x.level[i].forward.member) {
+			rank += x.level[i].span
+			x = x.level[i].forward
+		}
+		if x != zsl.header && x.member == member {
+			return rank
+		}
+	}
+	return 0
+}
+
+// byRank returns the node at 1-based rank, nil if out of range.
+func (zsl *zskiplist) byRank(rank int) *zskiplistNode {
+	traversed := 0
+	x := zsl.header
+	for i := zsl.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil && traversed+x.level[i].span <= rank {
+			traversed += x.level[i].span
+			x = x.level[i].forward
+		}
+		if traversed == rank && x != zsl.header {
+			return x
+		}
+	}
+	return nil
+}
+
+func (zsl *zskiplist) next(x *zskiplistNode, reverse bool) *zskiplistNode {
+	if reverse {
+		return x.backward
+	}
+	return x.level[0].forward
+}
+
+// skip moves n nodes on from x, in O(log n) through the ranks.
+func (zsl *zskiplist) skip(x *zskiplistNode, n int, reverse bool) *zskiplistNode {
+	if x == nil || n == 0 {
+		return x
+	}
+	rank := zsl.rank(x.score, x.member)
+	if reverse {
+		return zsl.byRank(rank - n)
+	}
+	return zsl.byRank(rank + n)
+}
+
+func (zsl *zskiplist) firstInScoreRange(r ScoreRange) *zskiplistNode {
+	if r.Empty() {
+		return nil
+	}
+	x := zsl.header
+	for i := zsl.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil && !r.aboveMin(x.level[i].forward.score) {
+			x = x.level[i].forward
+		}
+	}
+	x = x.level[0].forward
+	if x == nil || !r.belowMax(x.score) {
+		return nil
+	}
+	return x
+}
+
+func (zsl *zskiplist) lastInScoreRange(r ScoreRange) *zskiplistNode {
+	if r.Empty() {
+		return nil
+	}
+	x := zsl.header
+	for i := zsl.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil && r.belowMax(x.level[i].forward.score) {
+			x = x.level[i].forward
+		}
+	}
+	if x == zsl.header || !r.aboveMin(x.score) {
+		return nil
+	}
+	return x
+}
+
+func (zsl *zskiplist) firstInLexRange(r LexRange) *zskiplistNode {
+	if r.Empty() {
+		return nil
+	}
+	x := zsl.header
+	for i := zsl.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil && !r.Min.below(x.level[i].forward.member) {
+			x = x.level[i].forward
+		}
+	}
+	x = x.level[0].forward
+	if x == nil || !r.Max.above(x.member) {
+		return nil
+	}
+	return x
+}
+
+func (zsl *zskiplist) lastInLexRange(r LexRange) *zskiplistNode {
+	if r.Empty() {
+		return nil
+	}
+	x := zsl.header
+	for i := zsl.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil && r.Max.above(x.level[i].forward.member) {
+			x = x.level[i].forward
+		}
+	}
+	if x == zsl.header || !r.Min.below(x.member) {
+		return nil
+	}
+	return x
+}
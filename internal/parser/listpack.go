@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"encoding/binary"
+	"errors"
+	"strconv"
+)
+
+var errListpack = errors.New("invalid listpack")
+
+// listpackEntries returns the entries of a listpack, the compact encoding
+// Redis saves small hashes (and lists, sets and sorted sets) in, with
+// integers formatted in decimal. A listpack is a 6 byte header (total
+// bytes and number of entries), the entries and a 0xFF terminator. Each
+// entry is an encoding byte, which holds either a small integer, the
+// length of a string or the width of the integer following it, then its
+// data, then its own length again so the list can be walked backwards.
+func listpackEntries(lp []byte) ([]string, error) {
+	if len(lp) < 7 || int(binary.LittleEndian.Uint32(lp)) != len(lp) {
+		return nil, errListpack
+	}
+
+	var entries []string
+	p := lp[6:]
+	for len(p) > 0 && p[0] != 0xFF {
+		b := p[0]
+		var value string
+		var size int // of the encoding and data, without the backlen
+		switch {
+		case b&0x80 == 0: // 7 bit unsigned integer
+			value, size = strconv.Itoa(int(b)), 1
+		case b&0xC0 == 0x80: // string of up to 63 bytes
+			n := int(b & 0x3F)
+			if len(p) < 1+n {
+				return nil, errListpack
+			}
+			value, size = string(p[1:1+n]), 1+n
+		case b&0xE0 == 0xC0: // 13 bit signed integer
+			if len(p) < 2 {
+				return nil, errListpack
+			}
+			v := int64(b&0x1F)<<8 | int64(p[1])
+			value, size = strconv.FormatInt(signExtend(v, 13), 10), 2
+		case b&0xF0 == 0xE0: // string of up to 4095 bytes
+			if len(p) < 2 {
+				return nil, errListpack
+			}
+			n := int(b&0x0F)<<8 | int(p[1])
+			if len(p) < 2+n {
+				return nil, errListpack
+			}
+			value, size = string(p[2:2+n]), 2+n
+		case b == 0xF0: // string with a 32 bit length
+			if len(p) < 5 {
+				return nil, errListpack
+			}
+			n := int(binary.LittleEndian.Uint32(p[1:]))
+			if n < 0 || len(p)-5 < n {
+				return nil, errListpack
+			}
+			value, size = string(p[5:5+n]), 5+n
+		case b >= 0xF1 && b <= 0xF4: // 16, 24, 32 or 64 bit signed integer
+			width := [...]int{2, 3, 4, 8}[b-0xF1]
+			if len(p) < 1+width {
+				return nil, errListpack
+			}
+			var v uint64
+			for i := width; i > 0; i-- {
+				v = v<<8 | uint64(p[i])
+			}
+			value, size = strconv.FormatInt(signExtend(int64(v), width*8), 10), 1+width
+		default:
+			return nil, errListpack
+		}
+
+		size += backlenSize(size)
+		if len(p) < size {
+			return nil, errListpack
+		}
+		entries = append(entries, value)
+		p = p[size:]
+	}
+	if len(p) != 1 {
+		return nil, errListpack
+	}
+	return entries, nil
+}
+
+// signExtend interprets the low bits of v as a two's complement integer.
+func signExtend(v int64, bits int) int64 {
+	if bits < 64 && v&(1<<(bits-1)) != 0 {
+		v -= 1 << bits
+	}
+	return v
+}
+
+// backlenSize is how many bytes the backlen of an entry of size bytes
+// takes: 7 bits of the length per byte.
+func backlenSize(size int) int {
+	switch {
+	case size <= 127:
+		return 1
+	case size < 16383:
+		return 2
+	case size < 2097151:
+		return 3
+	case size < 268435455:
+		return 4
+	}
+	return 5
+}
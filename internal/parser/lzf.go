@@ -0,0 +1,54 @@
+package parser
+
+import "errors"
+
+var errLZF = errors.New("invalid LZF compressed string")
+
+// lzfDecompress expands data compressed with LZF, as Redis stores strings
+// longer than 20 bytes in RDB files, into exactly size bytes. Each chunk
+// starts with a control byte: below 32 it's followed by that many plus one
+// literal bytes, otherwise it's a back reference, with the length in its
+// top 3 bits (7 meaning "add the next byte") and the distance in the other
+// 5 and the byte after the length.
+func lzfDecompress(data []byte, size int) ([]byte, error) {
+	out := make([]byte, 0, size)
+	for i := 0; i < len(data); {
+		ctrl := int(data[i])
+		i++
+		if ctrl < 32 {
+			n := ctrl + 1
+			if i+n > len(data) || len(out)+n > size {
+				return nil, errLZF
+			}
+			out = append(out, data[i:i+n]...)
+			i += n
+			continue
+		}
+
+		n := ctrl >> 5
+		if n == 7 {
+			if i >= len(data) {
+				return nil, errLZF
+			}
+			n += int(data[i])
+			i++
+		}
+		if i >= len(data) {
+			return nil, errLZF
+		}
+		ref := len(out) - (ctrl&0x1f)<<8 - int(data[i]) - 1
+		i++
+		n += 2
+		if ref < 0 || len(out)+n > size {
+			return nil, errLZF
+		}
+		// byte by byte, as the reference may overlap what it produces
+		for j := 0; j < n; j++ {
+			out = append(out, out[ref+j])
+		}
+	}
+	if len(out) != size {
+		return nil, errLZF
+	}
+	return out, nil
+}
@@ -0,0 +1,154 @@
+package parser
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+)
+
+// maxInlineLen bounds a single inline request line.
+const maxInlineLen = 64 * 1024
+
+// parseInline reads an inline request: one line of space separated
+// arguments, as typed into telnet or nc. The line may end with either CRLF
+// or a bare LF.
+func parseInline(reader *bufio.Reader) ([]string, int64, error) {
+	var line []byte
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		line = append(line, chunk...)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return nil, int64(len(line)), err
+		}
+		if len(line) > maxInlineLen {
+			return nil, int64(len(line)), protocolErrorf("too big inline request")
+		}
+	}
+	n := int64(len(line))
+
+	text := strings.TrimSuffix(strings.TrimSuffix(string(line), "\n"), "\r")
+	args, err := SplitArgs(text)
+	if err != nil {
+		return nil, n, protocolErrorf("unbalanced quotes in request")
+	}
+	return args, n, nil
+}
+
+// SplitArgs splits a line into arguments the way redis-cli and the inline
+// protocol do: arguments are separated by whitespace, "double quoted"
+// arguments understand \n \r \t \b \a \\ \" and \xHH escapes, and 'single
+// quoted' ones only \'. A closing quote must be followed by whitespace or
+// the end of the line.
+func SplitArgs(line string) ([]string, error) {
+	var args []string
+	i := 0
+
+	for {
+		for i < len(line) && isSpace(line[i]) {
+			i++
+		}
+		if i == len(line) {
+			return args, nil
+		}
+
+		var current []byte
+		inDouble, inSingle := false, false
+		done := false
+
+		for !done {
+			switch {
+			case inDouble:
+				if i == len(line) {
+					return nil, errors.New("unbalanced quotes")
+				}
+				c := line[i]
+				if c == '\\' && i+3 < len(line) && line[i+1] == 'x' && isHex(line[i+2]) && isHex(line[i+3]) {
+					current = append(current, hexValue(line[i+2])<<4|hexValue(line[i+3]))
+					i += 3
+				} else if c == '\\' && i+1 < len(line) {
+					i++
+					switch line[i] {
+					case 'n':
+						current = append(current, '\n')
+					case 'r':
+						current = append(current, '\r')
+					case 't':
+						current = append(current, '\t')
+					case 'b':
+						current = append(current, '\b')
+					case 'a':
+						current = append(current, '\a')
+					default:
+						current = append(current, line[i])
+					}
+				} else if c == '"' {
+					// closing quote must be followed by a space or nothing at all
+					if i+1 < len(line) && !isSpace(line[i+1]) {
+						return nil, errors.New("unbalanced quotes")
+					}
+					done = true
+				} else {
+					current = append(current, c)
+				}
+			case inSingle:
+				if i == len(line) {
+					return nil, errors.New("unbalanced quotes")
+				}
+				c := line[i]
+				if c == '\\' && i+1 < len(line) && line[i+1] == '\'' {
+					i++
+					current = append(current, '\'')
+				} else if c == '\'' {
+					if i+1 < len(line) && !isSpace(line[i+1]) {
+						return nil, errors.New("unbalanced quotes")
+					}
+					done = true
+				} else {
+					current = append(current, c)
+				}
+			default:
+				if i == len(line) {
+					done = true
+					break
+				}
+				switch c := line[i]; {
+				case isSpace(c):
+					done = true
+				case c == '"':
+					inDouble = true
+				case c == '\'':
+					inSingle = true
+				default:
+					current = append(current, c)
+				}
+			}
+			if i < len(line) {
+				i++
+			}
+		}
+
+		args = append(args, string(current))
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\v' || c == '\f'
+}
+
+func isHex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func hexValue(c byte) byte {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0'
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
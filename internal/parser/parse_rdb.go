@@ -5,10 +5,20 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 
 	"github.com/codecrafters-io/redis-starter-go/internal/models/core"
 )
 
+// Value types of the RDB format that ParseRDB loads.
+const (
+	rdbTypeString         = 0
+	rdbTypeHash           = 4
+	rdbTypeHashListpack   = 16
+	rdbTypeHashMetadata   = 24 // a hash with field TTLs
+	rdbTypeHashListpackEx = 25 // a listpack hash with field TTLs
+)
+
 // ParseRDB will now open the RDB file and returns a map of key-value pairs
 // from the database section (ignoring metadata).
 func ParseRDB(filePath string) (map[string]core.StoreEntry, error) {
@@ -105,25 +115,30 @@ func ParseRDB(filePath string) (map[string]core.StoreEntry, error) {
 				if err != nil {
 					return nil, err
 				}
-				if valueType != 0 {
-					return nil, fmt.Errorf("unsupported value type: %x", valueType)
-				}
 
-				// read key and value as length prefixed strings.
 				key, err := readString(file)
 				if err != nil {
 					return nil, fmt.Errorf("error reading key: %v", err)
 				}
-				value, err := readString(file)
-				if err != nil {
-					return nil, fmt.Errorf("error reading value: %v", err)
-				}
 
-				entries[key] = core.StoreEntry{
-					Data:      value,
-					ExpiresAt: expiresAt,
-					Type:      "string",
+				entry := core.StoreEntry{ExpiresAt: expiresAt}
+				switch valueType {
+				case rdbTypeString:
+					entry.Type = "string"
+					entry.Data, err = readString(file)
+				case rdbTypeHash, rdbTypeHashMetadata:
+					entry.Type = "hash"
+					entry.Data, err = readHash(file, valueType == rdbTypeHashMetadata)
+				case rdbTypeHashListpack, rdbTypeHashListpackEx:
+					entry.Type = "hash"
+					entry.Data, err = readHashListpack(file, valueType == rdbTypeHashListpackEx)
+				default:
+					return nil, fmt.Errorf("unsupported value type: %x", valueType)
+				}
+				if err != nil {
+					return nil, fmt.Errorf("error reading value of %s: %v", key, err)
 				}
+				entries[key] = entry
 			}
 		default:
 			return nil, fmt.Errorf("unknown marker: %x", marker)
@@ -133,6 +148,93 @@ func ParseRDB(filePath string) (map[string]core.StoreEntry, error) {
 	return entries, nil
 }
 
+// readHash reads a hash stored as a plain list of fields and values. With
+// field TTLs (RDB_TYPE_HASH_METADATA, Redis 7.4) the list is preceded by
+// the earliest TTL, and each field by its TTL relative to that one, plus 1
+// (0 means no TTL).
+func readHash(file *os.File, withTTLs bool) (*core.Hash, error) {
+	var minExpire int64
+	if withTTLs {
+		buf := make([]byte, 8)
+		if _, err := io.ReadFull(file, buf); err != nil {
+			return nil, err
+		}
+		minExpire = int64(binary.LittleEndian.Uint64(buf))
+	}
+
+	n, err := readSize(file)
+	if err != nil {
+		return nil, err
+	}
+	hash := core.NewHash()
+	for i := 0; i < n; i++ {
+		var ttl int
+		if withTTLs {
+			if ttl, err = readSize(file); err != nil {
+				return nil, err
+			}
+		}
+		field, err := readString(file)
+		if err != nil {
+			return nil, err
+		}
+		value, err := readString(file)
+		if err != nil {
+			return nil, err
+		}
+
+		hash.Set(field, value)
+		if ttl != 0 {
+			hash.SetExpire(field, minExpire+int64(ttl)-1)
+		}
+	}
+	return hash, nil
+}
+
+// readHashListpack reads a small hash, saved as a listpack of alternating
+// fields and values. With field TTLs (RDB_TYPE_HASH_LISTPACK_EX, Redis
+// 7.4) the listpack is preceded by the earliest TTL, and each field is
+// followed by its value and its TTL as a unix time in ms (0 meaning no
+// TTL).
+func readHashListpack(file *os.File, withTTLs bool) (*core.Hash, error) {
+	step := 2
+	if withTTLs {
+		// the earliest TTL, which the fields' own TTLs tell us anyway
+		if _, err := io.ReadFull(file, make([]byte, 8)); err != nil {
+			return nil, err
+		}
+		step = 3
+	}
+
+	lp, err := readString(file)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := listpackEntries([]byte(lp))
+	if err != nil {
+		return nil, err
+	}
+	if len(entries)%step != 0 {
+		return nil, errListpack
+	}
+
+	hash := core.NewHash()
+	for i := 0; i < len(entries); i += step {
+		hash.Set(entries[i], entries[i+1])
+		if !withTTLs {
+			continue
+		}
+		ttl, err := strconv.ParseInt(entries[i+2], 10, 64)
+		if err != nil {
+			return nil, errListpack
+		}
+		if ttl != 0 {
+			hash.SetExpire(entries[i], ttl)
+		}
+	}
+	return hash, nil
+}
+
 func readByte(file *os.File) (byte, error) {
 	buf := make([]byte, 1)
 	_, err := file.Read(buf)
@@ -164,8 +266,34 @@ func readSize(file *os.File) (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	if b>>6 == 0 {
+	return readSizeFrom(file, b)
+}
+
+// readSizeFrom decodes a size whose first byte, b, was already read: the
+// top two bits say whether it's the remaining 6 bits, 14 bits with the next
+// byte, or (0x80 and 0x81) a big endian 32 or 64 bit number that follows.
+func readSizeFrom(file *os.File, b byte) (int, error) {
+	switch {
+	case b>>6 == 0:
 		return int(b & 0x3F), nil
+	case b>>6 == 1:
+		next, err := readByte(file)
+		if err != nil {
+			return 0, err
+		}
+		return int(b&0x3F)<<8 | int(next), nil
+	case b == 0x80:
+		buf := make([]byte, 4)
+		if _, err := io.ReadFull(file, buf); err != nil {
+			return 0, err
+		}
+		return int(binary.BigEndian.Uint32(buf)), nil
+	case b == 0x81:
+		buf := make([]byte, 8)
+		if _, err := io.ReadFull(file, buf); err != nil {
+			return 0, err
+		}
+		return int(binary.BigEndian.Uint64(buf)), nil
 	}
 	return 0, fmt.Errorf("size encoding not supported for byte: %x", b)
 }
@@ -180,8 +308,11 @@ func readString(file *os.File) (string, error) {
 		return "", err
 	}
 	switch firstByte >> 6 {
-	case 0:
-		length := int(firstByte & 0x3F)
+	case 0, 1, 2:
+		length, err := readSizeFrom(file, firstByte)
+		if err != nil {
+			return "", err
+		}
 		data := make([]byte, length)
 		if _, err := io.ReadFull(file, data); err != nil {
 			return "", err
@@ -211,6 +342,24 @@ func readString(file *os.File) (string, error) {
 			}
 			val := int(data[0]) | int(data[1])<<8 | int(data[2])<<16 | int(data[3])<<24
 			return fmt.Sprintf("%d", val), nil
+		case 3: // LZF compressed: compressed length, original length, data
+			clen, err := readSize(file)
+			if err != nil {
+				return "", err
+			}
+			length, err := readSize(file)
+			if err != nil {
+				return "", err
+			}
+			data := make([]byte, clen)
+			if _, err := io.ReadFull(file, data); err != nil {
+				return "", err
+			}
+			value, err := lzfDecompress(data, length)
+			if err != nil {
+				return "", err
+			}
+			return string(value), nil
 		default:
 			return "", fmt.Errorf("unsupported special string encoding type: %d", encType)
 		}
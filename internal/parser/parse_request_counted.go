@@ -3,49 +3,162 @@ package parser
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
+	"fmt"
+	"io"
 	"strconv"
-	"strings"
+	"sync/atomic"
 )
 
+const (
+	// MaxMultibulkLen caps the number of arguments in a single request.
+	MaxMultibulkLen = 1024 * 1024
+	// DefaultMaxBulkLen is the default proto-max-bulk-len (512mb).
+	DefaultMaxBulkLen = 512 * 1024 * 1024
+	// maxLineLen bounds the "*<count>" and "$<len>" header lines.
+	maxLineLen = 64 * 1024
+	// maxPrealloc caps what a header alone makes us allocate: larger bulk
+	// arguments (and argument lists) grow as their data actually arrives,
+	// so a client can't reserve proto-max-bulk-len per connection just by
+	// announcing it.
+	maxPrealloc = 64 * 1024
+)
+
+var maxBulkLen atomic.Int64
+
+func init() {
+	maxBulkLen.Store(DefaultMaxBulkLen)
+}
+
+// SetMaxBulkLen changes the largest bulk argument a client may send
+// (proto-max-bulk-len).
+func SetMaxBulkLen(n int64) {
+	maxBulkLen.Store(n)
+}
+
+func MaxBulkLen() int64 {
+	return maxBulkLen.Load()
+}
+
+// ProtocolError is returned when the client sent something that isn't valid
+// RESP. The connection can't be resynchronised afterwards, so callers reply
+// with the error and close it.
+type ProtocolError struct {
+	msg string
+}
+
+func (e *ProtocolError) Error() string {
+	return "Protocol error: " + e.msg
+}
+
+func protocolErrorf(format string, a ...interface{}) error {
+	return &ProtocolError{msg: fmt.Sprintf(format, a...)}
+}
+
+// ParseRequestWithByteCount reads one request and returns its arguments
+// along with the number of bytes consumed from the stream. Requests are
+// normally RESP multibulks, whose bulk arguments are read by their declared
+// length so they may contain any bytes, including CR and LF. Anything not
+// starting with '*' is an inline command (see parseInline). An empty inline
+// line yields no arguments.
 func ParseRequestWithByteCount(reader *bufio.Reader) ([]string, int64, error) {
 	var totalBytes int64 = 0
 
-	line, err := reader.ReadString('\n')
+	first, err := reader.Peek(1)
 	if err != nil {
 		return nil, 0, err
 	}
-	totalBytes += int64(len(line))
-
-	line = strings.TrimSpace(line)
-	if len(line) == 0 || line[0] != '*' {
-		return nil, totalBytes, errors.New("invalid request format")
+	if first[0] != '*' {
+		return parseInline(reader)
 	}
 
-	argCount, err := strconv.Atoi(line[1:])
-	if err != nil || argCount < 1 {
-		return nil, totalBytes, errors.New("invalid argument count")
+	line, n, err := readLine(reader)
+	totalBytes += n
+	if err != nil {
+		return nil, totalBytes, err
 	}
 
-	args := make([]string, 0, argCount)
+	argCount, err := strconv.ParseInt(string(line[1:]), 10, 64)
+	if err != nil || argCount > MaxMultibulkLen {
+		return nil, totalBytes, protocolErrorf("invalid multibulk length")
+	}
+	if argCount <= 0 {
+		return nil, totalBytes, nil
+	}
 
-	for i := 0; i < argCount; i++ {
+	args := make([]string, 0, min(argCount, maxPrealloc))
 
-		lengthLine, err := reader.ReadString('\n')
+	for i := int64(0); i < argCount; i++ {
+		lengthLine, n, err := readLine(reader)
+		totalBytes += n
 		if err != nil {
-			return nil, totalBytes, errors.New("unexpected end of input (length line)")
+			return nil, totalBytes, unexpectedEOF(err)
+		}
+		if len(lengthLine) == 0 || lengthLine[0] != '$' {
+			return nil, totalBytes, protocolErrorf("expected '$', got '%s'", firstChar(lengthLine))
 		}
-		totalBytes += int64(len(lengthLine))
 
-		argLine, err := reader.ReadString('\n')
-		if err != nil {
-			return nil, totalBytes, errors.New("unexpected end of input (arg line)")
+		bulkLen, err := strconv.ParseInt(string(lengthLine[1:]), 10, 64)
+		if err != nil || bulkLen < 0 || bulkLen > maxBulkLen.Load() {
+			return nil, totalBytes, protocolErrorf("invalid bulk length")
 		}
-		totalBytes += int64(len(argLine))
 
-		arg := strings.TrimSpace(argLine)
-		args = append(args, arg)
+		var payload bytes.Buffer
+		payload.Grow(int(min(bulkLen, maxPrealloc)))
+		if _, err := io.CopyN(&payload, reader, bulkLen); err != nil {
+			return nil, totalBytes, unexpectedEOF(err)
+		}
+		var crlf [2]byte
+		if _, err := io.ReadFull(reader, crlf[:]); err != nil {
+			return nil, totalBytes, unexpectedEOF(err)
+		}
+		totalBytes += bulkLen + 2
+
+		if crlf != [2]byte{'\r', '\n'} {
+			return nil, totalBytes, protocolErrorf("bulk string is not terminated by CRLF")
+		}
+		args = append(args, payload.String())
 	}
 
 	return args, totalBytes, nil
 }
+
+// readLine reads a CRLF terminated header line and returns it without the
+// terminator, along with the number of bytes consumed.
+func readLine(reader *bufio.Reader) ([]byte, int64, error) {
+	var line []byte
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		line = append(line, chunk...)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return nil, int64(len(line)), err
+		}
+		if len(line) > maxLineLen {
+			return nil, int64(len(line)), protocolErrorf("too big mbulk count string")
+		}
+	}
+
+	n := int64(len(line))
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return nil, n, protocolErrorf("line is not terminated by CRLF")
+	}
+	return line[:len(line)-2], n, nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func firstChar(line []byte) string {
+	if len(line) == 0 {
+		return ""
+	}
+	return string(line[:1])
+}
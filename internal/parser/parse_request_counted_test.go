@@ -0,0 +1,37 @@
+package parser
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseRequestLargeBulk(t *testing.T) {
+	value := strings.Repeat("x", 300*1024)
+	req := "*2\r\n$3\r\nSET\r\n$" + "307200" + "\r\n" + value + "\r\n"
+	args, n, err := ParseRequestWithByteCount(bufio.NewReader(strings.NewReader(req)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(args) != 2 || args[1] != value || n != int64(len(req)) {
+		t.Fatalf("got %d args, %d bytes", len(args), n)
+	}
+}
+
+func TestParseRequestDoesNotPreallocateDeclaredLength(t *testing.T) {
+	req := "*1\r\n$536870911\r\nshort"
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, _, err := ParseRequestWithByteCount(bufio.NewReader(strings.NewReader(req)))
+	runtime.ReadMemStats(&after)
+
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("err = %v, want unexpected EOF", err)
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+		t.Fatalf("allocated %d bytes for a 5 byte payload", allocated)
+	}
+}
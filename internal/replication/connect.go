@@ -20,7 +20,7 @@ func StartReplicaProcess(masterAddress, replicaPort string, handleCommand Comman
 		return
 	}
 	host, port := parts[0], parts[1]
-	address := fmt.Sprintf("%s:%s", host, port)
+	address := net.JoinHostPort(host, port)
 
 	fmt.Println("Connecting to master at", address)
 	conn, err := net.Dial("tcp", address)
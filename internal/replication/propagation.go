@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"strings"
 	"sync"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/resp"
 )
 
 var (
@@ -80,12 +83,38 @@ func PropagateCommand(command string, args []string) {
 	}
 }
 
+// PropagateCommands sends several commands to the replicas in one write,
+// so that nothing propagated meanwhile can get in between them, as a
+// MULTI ... EXEC block requires.
+func PropagateCommands(commands [][]string) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if len(replicas) == 0 {
+		return
+	}
+
+	var payload strings.Builder
+	for _, argv := range commands {
+		payload.WriteString(encodeCommandRESP(argv[0], argv[1:]))
+	}
+	AddToOffset(int64(payload.Len()))
+
+	fmt.Println("[Master] Propagating commands:", commands)
+
+	data := []byte(payload.String())
+	for _, rc := range replicas {
+		rc.Enqueue(data)
+	}
+}
+
 func encodeCommandRESP(command string, args []string) string {
-	resp := fmt.Sprintf("*%d\r\n$%d\r\n%s\r\n", len(args)+1, len(command), command)
+	buf := resp.AppendArrayLen(nil, len(args)+1)
+	buf = resp.AppendBulkString(buf, command)
 	for _, arg := range args {
-		resp += fmt.Sprintf("$%d\r\n%s\r\n", len(arg), arg)
+		buf = resp.AppendBulkString(buf, arg)
 	}
-	return resp
+	return string(buf)
 }
 
 func GetReplicaCount() int {
@@ -0,0 +1,169 @@
+// Package resp encodes RESP2 and RESP3 replies.
+//
+// Every Append* function appends one value to buf and returns the extended
+// slice, strconv.Append style. Types that only exist in RESP3 take the
+// client's protocol version and fall back to their closest RESP2 shape when
+// the client hasn't negotiated RESP3 with HELLO.
+package resp
+
+import (
+	"math"
+	"strconv"
+)
+
+const (
+	RESP2 = 2
+	RESP3 = 3
+)
+
+func AppendSimpleString(buf []byte, s string) []byte {
+	buf = append(buf, '+')
+	buf = append(buf, s...)
+	return append(buf, '\r', '\n')
+}
+
+// AppendError appends an error reply. msg must start with the error code,
+// e.g. "ERR syntax error".
+func AppendError(buf []byte, msg string) []byte {
+	buf = append(buf, '-')
+	buf = append(buf, msg...)
+	return append(buf, '\r', '\n')
+}
+
+func AppendInteger(buf []byte, n int64) []byte {
+	buf = append(buf, ':')
+	buf = strconv.AppendInt(buf, n, 10)
+	return append(buf, '\r', '\n')
+}
+
+func AppendBulkString(buf []byte, s string) []byte {
+	buf = append(buf, '$')
+	buf = strconv.AppendInt(buf, int64(len(s)), 10)
+	buf = append(buf, '\r', '\n')
+	buf = append(buf, s...)
+	return append(buf, '\r', '\n')
+}
+
+func AppendArrayLen(buf []byte, n int) []byte {
+	return appendLen(buf, '*', n)
+}
+
+// AppendNull appends a null: "_" in RESP3, the null bulk string in RESP2.
+func AppendNull(buf []byte, proto int) []byte {
+	if proto >= RESP3 {
+		return append(buf, '_', '\r', '\n')
+	}
+	return append(buf, "$-1\r\n"...)
+}
+
+// AppendNullArray appends a null: "_" in RESP3, the null array in RESP2.
+func AppendNullArray(buf []byte, proto int) []byte {
+	if proto >= RESP3 {
+		return append(buf, '_', '\r', '\n')
+	}
+	return append(buf, "*-1\r\n"...)
+}
+
+// AppendMapLen starts a map of n key/value pairs. RESP2 has no maps, so
+// it becomes a flat array of 2n elements.
+func AppendMapLen(buf []byte, proto int, n int) []byte {
+	if proto >= RESP3 {
+		return appendLen(buf, '%', n)
+	}
+	return appendLen(buf, '*', n*2)
+}
+
+// AppendSetLen starts a set of n elements, an array in RESP2.
+func AppendSetLen(buf []byte, proto int, n int) []byte {
+	if proto >= RESP3 {
+		return appendLen(buf, '~', n)
+	}
+	return appendLen(buf, '*', n)
+}
+
+// AppendPushLen starts an out-of-band push frame, an array in RESP2.
+func AppendPushLen(buf []byte, proto int, n int) []byte {
+	if proto >= RESP3 {
+		return appendLen(buf, '>', n)
+	}
+	return appendLen(buf, '*', n)
+}
+
+// AppendDouble appends a double, a bulk string in RESP2.
+func AppendDouble(buf []byte, proto int, f float64) []byte {
+	return AppendFormattedDouble(buf, proto, FormatDouble(f))
+}
+
+// AppendFormattedDouble is AppendDouble for a double that the caller
+// formatted itself, for replies that use a fixed precision.
+func AppendFormattedDouble(buf []byte, proto int, s string) []byte {
+	if proto >= RESP3 {
+		buf = append(buf, ',')
+		buf = append(buf, s...)
+		return append(buf, '\r', '\n')
+	}
+	return AppendBulkString(buf, s)
+}
+
+// AppendBool appends a boolean, the integers 1 and 0 in RESP2.
+func AppendBool(buf []byte, proto int, b bool) []byte {
+	if proto >= RESP3 {
+		if b {
+			return append(buf, "#t\r\n"...)
+		}
+		return append(buf, "#f\r\n"...)
+	}
+	if b {
+		return AppendInteger(buf, 1)
+	}
+	return AppendInteger(buf, 0)
+}
+
+// AppendBigNumber appends an arbitrary precision integer given in decimal,
+// a bulk string in RESP2.
+func AppendBigNumber(buf []byte, proto int, n string) []byte {
+	if proto >= RESP3 {
+		buf = append(buf, '(')
+		buf = append(buf, n...)
+		return append(buf, '\r', '\n')
+	}
+	return AppendBulkString(buf, n)
+}
+
+// AppendVerbatim appends a verbatim string with a three letter format such
+// as "txt" or "mkd", a plain bulk string in RESP2.
+func AppendVerbatim(buf []byte, proto int, format, s string) []byte {
+	if proto >= RESP3 {
+		buf = append(buf, '=')
+		buf = strconv.AppendInt(buf, int64(len(s)+4), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, format...)
+		buf = append(buf, ':')
+		buf = append(buf, s...)
+		return append(buf, '\r', '\n')
+	}
+	return AppendBulkString(buf, s)
+}
+
+// FormatDouble formats f the way Redis prints doubles: the shortest
+// representation that round-trips (integral values without an exponent),
+// and "inf", "-inf" or "nan".
+func FormatDouble(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	case math.IsNaN(f):
+		return "nan"
+	case f == math.Trunc(f) && math.Abs(f) < 1e17:
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func appendLen(buf []byte, prefix byte, n int) []byte {
+	buf = append(buf, prefix)
+	buf = strconv.AppendInt(buf, int64(n), 10)
+	return append(buf, '\r', '\n')
+}
@@ -0,0 +1,94 @@
+package resp
+
+import (
+	"io"
+)
+
+// flushThreshold is how much output a Writer buffers before writing it out
+// on its own, so a huge reply doesn't have to be held in memory entirely.
+const flushThreshold = 64 * 1024
+
+// Writer buffers the replies for one client. Replies are only sent when
+// Flush is called (or the buffer grows past flushThreshold), which lets a
+// whole batch of pipelined commands go out in a single write. Encoding
+// errors can't happen; write errors are sticky and reported by Flush.
+//
+// A Writer with a nil destination discards everything written to it.
+type Writer struct {
+	dst   io.Writer
+	buf   []byte
+	proto int
+	err   error
+}
+
+func NewWriter(dst io.Writer) *Writer {
+	return &Writer{dst: dst, proto: RESP2}
+}
+
+// Protocol is the RESP version replies are encoded in.
+func (w *Writer) Protocol() int {
+	return w.proto
+}
+
+func (w *Writer) SetProtocol(proto int) {
+	w.proto = proto
+}
+
+// Buffered returns the number of bytes waiting to be flushed.
+func (w *Writer) Buffered() int {
+	return len(w.buf)
+}
+
+// Flush writes out everything buffered so far.
+func (w *Writer) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	if len(w.buf) == 0 || w.dst == nil {
+		w.buf = w.buf[:0]
+		return nil
+	}
+
+	_, w.err = w.dst.Write(w.buf)
+	w.buf = w.buf[:0]
+	return w.err
+}
+
+func (w *Writer) SimpleString(s string)    { w.done(AppendSimpleString(w.buf, s)) }
+func (w *Writer) OK()                      { w.done(append(w.buf, "+OK\r\n"...)) }
+func (w *Writer) Error(msg string)         { w.done(AppendError(w.buf, msg)) }
+func (w *Writer) Integer(n int64)          { w.done(AppendInteger(w.buf, n)) }
+func (w *Writer) Bulk(s string)            { w.done(AppendBulkString(w.buf, s)) }
+func (w *Writer) ArrayLen(n int)           { w.done(AppendArrayLen(w.buf, n)) }
+func (w *Writer) Null()                    { w.done(AppendNull(w.buf, w.proto)) }
+func (w *Writer) NullArray()               { w.done(AppendNullArray(w.buf, w.proto)) }
+func (w *Writer) MapLen(n int)             { w.done(AppendMapLen(w.buf, w.proto, n)) }
+func (w *Writer) SetLen(n int)             { w.done(AppendSetLen(w.buf, w.proto, n)) }
+func (w *Writer) PushLen(n int)            { w.done(AppendPushLen(w.buf, w.proto, n)) }
+func (w *Writer) Double(f float64)         { w.done(AppendDouble(w.buf, w.proto, f)) }
+func (w *Writer) FormattedDouble(s string) { w.done(AppendFormattedDouble(w.buf, w.proto, s)) }
+func (w *Writer) Bool(b bool)              { w.done(AppendBool(w.buf, w.proto, b)) }
+func (w *Writer) BigNumber(n string)       { w.done(AppendBigNumber(w.buf, w.proto, n)) }
+
+func (w *Writer) Verbatim(format, s string) {
+	w.done(AppendVerbatim(w.buf, w.proto, format, s))
+}
+
+// BulkArray writes an array of bulk strings.
+func (w *Writer) BulkArray(items []string) {
+	buf := AppendArrayLen(w.buf, len(items))
+	for _, item := range items {
+		buf = AppendBulkString(buf, item)
+	}
+	w.done(buf)
+}
+
+// Raw appends already encoded bytes, e.g. the RDB payload sent to a replica.
+func (w *Writer) Raw(b []byte) { w.done(append(w.buf, b...)) }
+
+func (w *Writer) done(buf []byte) {
+	w.buf = buf
+	if len(w.buf) >= flushThreshold {
+		w.Flush()
+	}
+}
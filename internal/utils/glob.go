@@ -0,0 +1,123 @@
+package utils
+
+// GlobMatch reports whether s matches the glob-style pattern, with the
+// same rules as Redis's stringmatchlen: '*' matches any run of bytes, '?'
+// any single byte, "[abc]", "[^abc]" and "[a-z]" match classes of bytes,
+// and '\' escapes the next pattern byte.
+func GlobMatch(pattern, s string, nocase bool) bool {
+	skipLongerMatches := false
+	return globMatch(pattern, s, nocase, &skipLongerMatches, 0)
+}
+
+func globMatch(pattern, s string, nocase bool, skipLongerMatches *bool, nesting int) bool {
+	// protection against abusive patterns
+	if nesting > 1000 {
+		return false
+	}
+
+	for len(pattern) > 0 && len(s) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 1 {
+				return true
+			}
+			for len(s) > 0 {
+				if globMatch(pattern[1:], s, nocase, skipLongerMatches, nesting+1) {
+					return true
+				}
+				if *skipLongerMatches {
+					return false
+				}
+				s = s[1:]
+			}
+			// The rest of the pattern matches nowhere in the rest of the
+			// string, so no earlier '*' can help by matching more.
+			*skipLongerMatches = true
+			return false
+		case '?':
+			s = s[1:]
+		case '[':
+			pattern = pattern[1:]
+			not := len(pattern) > 0 && pattern[0] == '^'
+			if not {
+				pattern = pattern[1:]
+			}
+			match := false
+			for {
+				if len(pattern) == 0 {
+					// unterminated class: stop on its last byte, which the
+					// pattern advance below consumes
+					pattern = " "
+					break
+				}
+				if pattern[0] == '\\' && len(pattern) >= 2 {
+					pattern = pattern[1:]
+					if pattern[0] == s[0] {
+						match = true
+					}
+				} else if pattern[0] == ']' {
+					break
+				} else if len(pattern) >= 3 && pattern[1] == '-' {
+					start, end, c := pattern[0], pattern[2], s[0]
+					if start > end {
+						start, end = end, start
+					}
+					if nocase {
+						start, end, c = toLower(start), toLower(end), toLower(c)
+					}
+					pattern = pattern[2:]
+					if c >= start && c <= end {
+						match = true
+					}
+				} else if equalByte(pattern[0], s[0], nocase) {
+					match = true
+				}
+				pattern = pattern[1:]
+			}
+			if not {
+				match = !match
+			}
+			if !match {
+				return false
+			}
+			s = s[1:]
+		case '\\':
+			if len(pattern) >= 2 {
+				pattern = pattern[1:]
+			}
+			fallthrough
+		default:
+			if !equalByte(pattern[0], s[0], nocase) {
+				return false
+			}
+			s = s[1:]
+		}
+
+		pattern = pattern[1:]
+		if len(s) == 0 {
+			for len(pattern) > 0 && pattern[0] == '*' {
+				pattern = pattern[1:]
+			}
+			break
+		}
+	}
+
+	return len(pattern) == 0 && len(s) == 0
+}
+
+func equalByte(a, b byte, nocase bool) bool {
+	if nocase {
+		return toLower(a) == toLower(b)
+	}
+	return a == b
+}
+
+func toLower(b byte) byte {
+	if b >= 'A' && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+	return b
+}